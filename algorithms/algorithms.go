@@ -0,0 +1,159 @@
+// Package algorithms implements graph algorithms over the structures provided
+// by the graphtools library
+package algorithms
+
+import (
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// EdgeKey identifies an edge by the IDs of its near and far nodes. A graph
+// holds at most one edge in each direction between two nodes, so an EdgeKey is
+// unique within a graph
+type EdgeKey struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// KeyOf returns the EdgeKey of edge e
+func KeyOf(e *structures.Edge) EdgeKey {
+	return EdgeKey{e.Nodes[0].ID, e.Nodes[1].ID}
+}
+
+// Reverse returns the key of the edge in the opposite direction
+func (k EdgeKey) Reverse() EdgeKey {
+	return EdgeKey{k.To, k.From}
+}
+
+// undirectedKey returns the key of e with the lower node ID first, so that an
+// edge and its reverse edge share a key
+func undirectedKey(e *structures.Edge) EdgeKey {
+	return undirectedKeyOf(e.Nodes[0].ID, e.Nodes[1].ID)
+}
+
+// undirectedKeyOf returns the key of the undirected edge between two nodes
+// with the lower node ID first
+func undirectedKeyOf(a, b int) EdgeKey {
+	if b < a {
+		return EdgeKey{b, a}
+	}
+	return EdgeKey{a, b}
+}
+
+// Visitor receives events as an algorithm progresses. It is useful for
+// observing or animating an algorithm step by step. Any of the functions may be
+// nil, as may the Visitor itself
+type Visitor struct {
+	// DiscoverNode is called when a node is first reached, e.g. when it is
+	// added to a queue or frontier
+	DiscoverNode func(id int)
+	// ExamineNode is called when a node becomes the current node
+	ExamineNode func(id int)
+	// ExamineEdge is called when an edge is considered from its near node
+	ExamineEdge func(e *structures.Edge)
+	// FinishNode is called when the algorithm is done with a node
+	FinishNode func(id int)
+	// SelectEdge is called when an edge becomes part of the result, e.g. a
+	// spanning tree
+	SelectEdge func(e *structures.Edge)
+	// AssignFlow is called when the flow along an edge changes
+	AssignFlow func(e *structures.Edge, flow float64)
+	// MatchEdge is called when an edge joins or leaves a matching
+	MatchEdge func(e *structures.Edge, matched bool)
+	// ContractBlossom is called when the nodes of an odd cycle are contracted
+	// into a blossom with the specified base node. nodes holds the IDs of every
+	// node in the blossom, including those of nested blossoms
+	ContractBlossom func(base int, nodes []int)
+	// ExpandBlossom is called when a blossom is expanded back into its nodes
+	ExpandBlossom func(base int, nodes []int)
+	// UpdateTour is called when a tour through every node improves. tour
+	// holds the node IDs in visiting order, without returning to the first
+	UpdateTour func(tour []int)
+}
+
+func (v *Visitor) discoverNode(id int) {
+	if v != nil && v.DiscoverNode != nil {
+		v.DiscoverNode(id)
+	}
+}
+
+func (v *Visitor) examineNode(id int) {
+	if v != nil && v.ExamineNode != nil {
+		v.ExamineNode(id)
+	}
+}
+
+func (v *Visitor) examineEdge(e *structures.Edge) {
+	if v != nil && v.ExamineEdge != nil {
+		v.ExamineEdge(e)
+	}
+}
+
+func (v *Visitor) finishNode(id int) {
+	if v != nil && v.FinishNode != nil {
+		v.FinishNode(id)
+	}
+}
+
+func (v *Visitor) selectEdge(e *structures.Edge) {
+	if v != nil && v.SelectEdge != nil {
+		v.SelectEdge(e)
+	}
+}
+
+func (v *Visitor) assignFlow(e *structures.Edge, flow float64) {
+	if v != nil && v.AssignFlow != nil {
+		v.AssignFlow(e, flow)
+	}
+}
+
+func (v *Visitor) matchEdge(e *structures.Edge, matched bool) {
+	if v != nil && v.MatchEdge != nil {
+		v.MatchEdge(e, matched)
+	}
+}
+
+func (v *Visitor) contractBlossom(base int, nodes []int) {
+	if v != nil && v.ContractBlossom != nil {
+		v.ContractBlossom(base, nodes)
+	}
+}
+
+func (v *Visitor) expandBlossom(base int, nodes []int) {
+	if v != nil && v.ExpandBlossom != nil {
+		v.ExpandBlossom(base, nodes)
+	}
+}
+
+func (v *Visitor) updateTour(tour []int) {
+	if v != nil && v.UpdateTour != nil {
+		v.UpdateTour(tour)
+	}
+}
+
+// sourceNodes returns the nodes with the requested IDs, or every node in the
+// graph if no IDs are requested
+func sourceNodes(g *structures.Graph, ids []int) ([]*structures.Node, error) {
+	if len(ids) == 0 {
+		return g.Nodes, nil
+	}
+
+	nodes := make([]*structures.Node, len(ids))
+	for i, id := range ids {
+		n, err := g.GetNodeByID(id)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = n
+	}
+	return nodes, nil
+}
+
+// copyNodes returns a graph with the storage order and maximum edge weight of
+// g that holds a copy of every node of g and no edges
+func copyNodes(g *structures.Graph) *structures.Graph {
+	c := structures.NewGraphWithOrder(g.MaxEdgeWeight, g.StorageOrder())
+	for _, n := range g.Nodes {
+		c.SetNodeByID(n.ID, n.Coords.X, n.Coords.Y, n.Coords.Z, n.Extra)
+	}
+	return c
+}
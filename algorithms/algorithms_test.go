@@ -0,0 +1,63 @@
+package algorithms
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	os.Exit(code)
+}
+
+// mockEdge is a weighted edge between two node IDs for building test graphs
+type mockEdge struct {
+	from, to int
+	w        float64
+}
+
+// mockGraph creates a graph with nodes 0 through n-1 laid out along a line and
+// the requested directed edges
+func mockGraph(t testing.TB, n int, edges []mockEdge) *structures.Graph {
+	t.Helper()
+	g := structures.NewGraph(1000)
+	for i := 0; i < n; i++ {
+		g.SetNodeByID(i, float64(i), 0, 0, structures.ColorData{
+			Color: structures.Colors["orange"],
+			Type:  structures.DataNodeTag,
+		})
+	}
+	for _, e := range edges {
+		err := g.SetEdgeByNodeID(e.from, e.to, e.w, "n", "n", false)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not add edge from %d to %d: %v", e.from, e.to, err))
+		}
+	}
+	return g
+}
+
+// mockUndirectedGraph creates a graph like mockGraph where every edge is
+// bidirectional
+func mockUndirectedGraph(t testing.TB, n int, edges []mockEdge) *structures.Graph {
+	t.Helper()
+	both := make([]mockEdge, 0, 2*len(edges))
+	for _, e := range edges {
+		both = append(both, e, mockEdge{e.to, e.from, e.w})
+	}
+	return mockGraph(t, n, both)
+}
+
+func checkInts(t *testing.T, name string, got, want []int) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf(fmt.Sprintf("%s should be %v but is %v", name, want, got))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf(fmt.Sprintf("%s should be %v but is %v", name, want, got))
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package algorithms
+
+import (
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// DistanceMatrix holds the shortest paths between every pair of nodes of a
+// graph. Rows and columns follow the order of the graph's nodes
+type DistanceMatrix struct {
+	// IDs holds the node ID of each row and column
+	IDs []int `json:"ids"`
+	// Dist holds the cost of the shortest path from the node of each row to
+	// the node of each column. Unreachable pairs cost +Inf
+	Dist [][]float64 `json:"dist"`
+
+	// parent holds the index of the previous node on the shortest path from
+	// the node of each row to the node of each column, or -1 if there is none
+	parent [][]int
+	index  map[int]int
+}
+
+func newDistanceMatrix(g *structures.Graph) *DistanceMatrix {
+	n := len(g.Nodes)
+	m := &DistanceMatrix{
+		IDs:    make([]int, n),
+		Dist:   make([][]float64, n),
+		parent: make([][]int, n),
+		index:  make(map[int]int, n),
+	}
+	for i, node := range g.Nodes {
+		m.IDs[i] = node.ID
+		m.index[node.ID] = i
+		m.Dist[i] = make([]float64, n)
+		m.parent[i] = make([]int, n)
+		for j := range m.Dist[i] {
+			m.Dist[i][j] = math.Inf(1)
+			m.parent[i][j] = -1
+		}
+		m.Dist[i][i] = 0
+	}
+	return m
+}
+
+// Distance returns the cost of the shortest path from one node to another
+func (m *DistanceMatrix) Distance(from, to int) (float64, error) {
+	i, iok := m.index[from]
+	j, jok := m.index[to]
+	if !iok || !jok || math.IsInf(m.Dist[i][j], 1) {
+		return 0, &NoPathError{from, to, nil}
+	}
+	return m.Dist[i][j], nil
+}
+
+// Path returns the shortest path from one node to another
+func (m *DistanceMatrix) Path(from, to int) (*Path, error) {
+	cost, err := m.Distance(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	i, j := m.index[from], m.index[to]
+	nodes := []int{to}
+	for k := m.parent[i][j]; k != -1; k = m.parent[i][k] {
+		nodes = append(nodes, m.IDs[k])
+	}
+	reverseInts(nodes)
+	return &Path{nodes, cost}, nil
+}
+
+// Eccentricity returns the cost of the longest shortest path from each node.
+// Nodes that cannot reach every other node have infinite eccentricity
+func (m *DistanceMatrix) Eccentricity() map[int]float64 {
+	ecc := make(map[int]float64, len(m.IDs))
+	for i, id := range m.IDs {
+		e := 0.0
+		for _, d := range m.Dist[i] {
+			e = math.Max(e, d)
+		}
+		ecc[id] = e
+	}
+	return ecc
+}
+
+// Diameter returns the greatest eccentricity of any node, which is +Inf unless
+// the graph is strongly connected
+func (m *DistanceMatrix) Diameter() float64 {
+	diameter := 0.0
+	for _, e := range m.Eccentricity() {
+		diameter = math.Max(diameter, e)
+	}
+	return diameter
+}
+
+// Radius returns the least eccentricity of any node
+func (m *DistanceMatrix) Radius() float64 {
+	if len(m.IDs) == 0 {
+		return 0
+	}
+
+	radius := math.Inf(1)
+	for _, e := range m.Eccentricity() {
+		radius = math.Min(radius, e)
+	}
+	return radius
+}
+
+// Center returns the IDs of the nodes whose eccentricity equals the radius, in
+// row order
+func (m *DistanceMatrix) Center() []int {
+	ecc := m.Eccentricity()
+	radius := m.Radius()
+
+	var center []int
+	for _, id := range m.IDs {
+		if ecc[id] == radius {
+			center = append(center, id)
+		}
+	}
+	return center
+}
+
+// FloydWarshall finds the shortest paths between every pair of nodes by
+// allowing each node in turn as an intermediate node. Edge weights may be
+// negative. If g has a negative cycle, a *NegativeCycleError holding the cycle
+// is returned
+func FloydWarshall(g *structures.Graph) (*DistanceMatrix, error) {
+	m := newDistanceMatrix(g)
+	for i, n := range g.Nodes {
+		for _, e := range n.Edges {
+			j := m.index[e.Nodes[1].ID]
+			if e.Weight < m.Dist[i][j] {
+				m.Dist[i][j] = e.Weight
+				m.parent[i][j] = i
+			}
+		}
+	}
+
+	for k := range m.IDs {
+		for i := range m.IDs {
+			dik := m.Dist[i][k]
+			if math.IsInf(dik, 1) {
+				continue
+			}
+			for j := range m.IDs {
+				if d := dik + m.Dist[k][j]; d < m.Dist[i][j] {
+					m.Dist[i][j] = d
+					m.parent[i][j] = m.parent[k][j]
+				}
+			}
+		}
+	}
+
+	// A node on a negative cycle has a negative cost path to itself. Parent
+	// pointers through a negative cycle can be inconsistent, so the cycle is
+	// found again by Bellman-Ford
+	for i := range m.IDs {
+		if m.Dist[i][i] < 0 {
+			return nil, &NegativeCycleError{FindNegativeCycle(g), nil}
+		}
+	}
+	return m, nil
+}
+
+// Johnson finds the shortest paths between every pair of nodes by reweighting
+// edges to be non-negative and running Dijkstra from every node. The Dijkstra
+// searches run in parallel. Edge weights may be negative. If g has a negative
+// cycle, a *NegativeCycleError holding the cycle is returned. Johnson is faster
+// than FloydWarshall on sparse graphs
+func Johnson(g *structures.Graph) (*DistanceMatrix, error) {
+	// The costs from a virtual source give each node a potential h such that
+	// w(u, v) + h(u) - h(v) is never negative. Reweighting changes the cost
+	// of every path between two nodes by the same amount, so shortest paths
+	// are kept
+	h, parent := virtualSourceDistances(g)
+	if cycle := bellmanFord(g, nil, h, parent, nil); cycle != nil {
+		return nil, &NegativeCycleError{cycle, nil}
+	}
+	reweight := func(e *structures.Edge) float64 {
+		// Rounding can leave a zero weight slightly negative
+		return math.Max(0, e.Weight+h[e.Nodes[0].ID]-h[e.Nodes[1].ID])
+	}
+
+	m := newDistanceMatrix(g)
+	errs := make([]error, len(g.Nodes))
+	rows := make(chan int)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rows {
+				errs[i] = m.setRow(g, i, h, reweight)
+			}
+		}()
+	}
+	for i := range g.Nodes {
+		rows <- i
+	}
+	close(rows)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// setRow fills row i with the shortest paths from its node over the reweighted
+// edges, converting costs back with the potentials h
+func (m *DistanceMatrix) setRow(g *structures.Graph, i int, h map[int]float64, w weightFunc) error {
+	s := g.Nodes[i]
+	sp, err := bestFirstSearch(g, nil, s, nil, nil, w)
+	if err != nil {
+		return err
+	}
+
+	for id, d := range sp.Dist {
+		j := m.index[id]
+		m.Dist[i][j] = d - h[s.ID] + h[id]
+		if p, ok := sp.Parent[id]; ok {
+			m.parent[i][j] = m.index[p]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestAllPairs(t *testing.T) {
+	log.Printf("Testing all-pairs shortest paths")
+
+	edges := []mockEdge{
+		{0, 1, 4}, {0, 2, 5}, {1, 3, -3}, {2, 1, -2}, {3, 4, 2}, {2, 4, 4},
+		{4, 0, 1}, {5, 0, 1},
+	}
+	cycleEdges := []mockEdge{
+		{0, 1, 1}, {1, 2, 2}, {2, 3, -4}, {3, 1, 1}, {3, 4, 1}, {5, 0, 1},
+	}
+
+	algs := map[string]func(*structures.Graph) (*DistanceMatrix, error){
+		"Floyd-Warshall": FloydWarshall,
+		"Johnson":        Johnson,
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			g := mockGraph(t, 6, edges)
+			m, err := alg(g)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find shortest paths: %v", err))
+			}
+
+			// Every row agrees with Bellman-Ford from that row's node
+			for _, from := range m.IDs {
+				sp, _ := BellmanFord(g, nil, from)
+				for _, to := range m.IDs {
+					d, err := m.Distance(from, to)
+					want, ok := sp.Dist[to]
+					if !ok {
+						var noPath *NoPathError
+						if !errors.As(err, &noPath) {
+							t.Fatalf(fmt.Sprintf("Distance from %d to %d should fail with NoPathError", from, to))
+						}
+						continue
+					}
+					if err != nil || d != want {
+						t.Fatalf(fmt.Sprintf("Distance from %d to %d should be %f but is %f", from, to, want, d))
+					}
+
+					p, err := m.Path(from, to)
+					if err != nil {
+						t.Fatalf(fmt.Sprintf("Could not find path from %d to %d: %v", from, to, err))
+					}
+					checkPathCost(t, g, p)
+				}
+			}
+
+			p, _ := m.Path(0, 4)
+			checkInts(t, "Path from 0 to 4", p.Nodes, []int{0, 2, 1, 3, 4})
+			p, _ = m.Path(3, 3)
+			checkInts(t, "Path from 3 to 3", p.Nodes, []int{3})
+
+			g = mockGraph(t, 6, cycleEdges)
+			_, err = alg(g)
+			var negative *NegativeCycleError
+			if !errors.As(err, &negative) {
+				t.Fatalf("All-pairs shortest paths with a negative cycle should fail with NegativeCycleError")
+			}
+			checkNegativeCycle(t, g, negative.Cycle)
+		})
+	}
+
+	t.Run("Eccentricity", func(t *testing.T) {
+		// A path of five nodes with unit weights
+		g := mockUndirectedGraph(t, 5, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}})
+		m, err := Johnson(g)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find shortest paths: %v", err))
+		}
+
+		checkDistances(t, m.Eccentricity(), map[int]float64{0: 4, 1: 3, 2: 2, 3: 3, 4: 4})
+		if m.Diameter() != 4 {
+			t.Fatalf(fmt.Sprintf("Diameter should be %f but is %f", 4.0, m.Diameter()))
+		}
+		if m.Radius() != 2 {
+			t.Fatalf(fmt.Sprintf("Radius should be %f but is %f", 2.0, m.Radius()))
+		}
+		checkInts(t, "Center", m.Center(), []int{2})
+
+		// Node 5 cannot reach or be reached from the path
+		g.SetNodeByID(5, 5, 0, 0, nil)
+		m, _ = FloydWarshall(g)
+		if !math.IsInf(m.Diameter(), 1) {
+			t.Fatalf(fmt.Sprintf("Diameter of a disconnected graph should be +Inf but is %f", m.Diameter()))
+		}
+	})
+}
+
+func checkPathCost(t *testing.T, g *structures.Graph, p *Path) {
+	t.Helper()
+	w := 0.0
+	for _, k := range p.Edges() {
+		e, err := g.GetEdgeByNodeID(k.From, k.To)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Path %v should follow graph edges", p.Nodes))
+		}
+		w += e.Weight
+	}
+	if w != p.Cost {
+		t.Fatalf(fmt.Sprintf("Path %v should cost %f but costs %f", p.Nodes, p.Cost, w))
+	}
+}
@@ -0,0 +1,504 @@
+package algorithms
+
+import (
+	"sync"
+	"time"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+const (
+	// AlgorithmRunType names the algorithm run manager for use in API
+	// operations
+	AlgorithmRunType = "algorithm"
+)
+
+// State is the display state of a node or edge during an algorithm run
+type State int
+
+const (
+	// Unvisited nodes and edges have not been reached by the algorithm
+	Unvisited State = iota
+	// Frontier nodes and edges have been reached but not yet processed
+	Frontier
+	// Current nodes and edges are being processed
+	Current
+	// Visited nodes and edges have been processed
+	Visited
+	// Selected nodes and edges are part of the result, e.g. a path or tree
+	Selected
+	// Saturated edges carry as much flow as their capacity allows
+	Saturated
+	// Contracted nodes are part of a contracted blossom
+	Contracted
+)
+
+var (
+	// StateColors maps display states onto the ColorData palette
+	StateColors map[State]string = map[State]string{
+		Unvisited:  structures.Colors["orange"],
+		Frontier:   structures.Colors["yellow"],
+		Current:    structures.Colors["red"],
+		Visited:    structures.Colors["green"],
+		Selected:   structures.Colors["blue"],
+		Saturated:  structures.Colors["purple"],
+		Contracted: structures.Colors["black"],
+	}
+)
+
+// stateChange is the change of display state of one node or edge
+type stateChange struct {
+	isEdge bool
+	id     int
+	key    EdgeKey
+	from   State
+	to     State
+}
+
+// frame holds the state changes that make up one step of an algorithm run
+type frame []stateChange
+
+// RunManager animates an algorithm over a graph. It wraps the display manager
+// that owns the graph and records the algorithm as a history of frames, each of
+// which recolors the nodes and edges changed by one step. The frames are then
+// played back through the wrapped manager, one update per step. Because every
+// frame records the states it replaces, stepping back replays earlier states
+// without running the algorithm again
+type RunManager struct {
+	structures.GraphDisplayManager `json:"-"`
+
+	Graph     *structures.Graph `json:"graph"`
+	Type      string            `json:"type"`
+	Algorithm string            `json:"algorithm"`
+	Step      int               `json:"step"`
+	NumSteps  int               `json:"numSteps"`
+	Playing   bool              `json:"playing"`
+
+	// StepDelay is the pause between steps during playback
+	StepDelay time.Duration `json:"-"`
+
+	// Recording structures
+	nodeStates map[int]State
+	edgeStates map[EdgeKey]State
+	pending    frame
+	frames     []frame
+	current    int
+	hasCurrent bool
+
+	// Playback control structures. control is always acquired before the
+	// display lock of the wrapped manager. stop is closed to pause playback
+	// and stopped is closed once the playback goroutine has exited
+	control *sync.Mutex
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewRunManager creates a run manager for graph g, which is owned by display
+// manager mgr
+func NewRunManager(mgr structures.GraphDisplayManager, g *structures.Graph) *RunManager {
+	m := new(RunManager)
+	m.GraphDisplayManager = mgr
+	m.Graph = g
+	m.Type = AlgorithmRunType
+	m.StepDelay = 250 * time.Millisecond
+	m.nodeStates = make(map[int]State)
+	m.edgeStates = make(map[EdgeKey]State)
+	m.control = &sync.Mutex{}
+
+	return m
+}
+
+// Run resets the display state of the graph, records alg as a history of
+// frames and starts playing them back. alg is passed a Visitor that takes one
+// step per algorithm event, and may also call SetNodeState, SetEdgeState and
+// TakeStep directly
+func (m *RunManager) Run(name string, alg func(v *Visitor) error) error {
+	m.control.Lock()
+	m.pause()
+	m.frames = nil
+	m.pending = nil
+	m.hasCurrent = false
+
+	m.Lock()
+	m.Algorithm = name
+	m.Step = 0
+	m.NumSteps = 0
+	ResetSizes(m.Graph)
+	for _, n := range m.Graph.Nodes {
+		m.nodeStates[n.ID] = Unvisited
+		m.showNodeState(n.ID, Unvisited)
+		for _, e := range n.Edges {
+			m.edgeStates[KeyOf(e)] = Unvisited
+			m.showEdgeState(KeyOf(e), Unvisited)
+		}
+	}
+	m.Unlock()
+	m.control.Unlock()
+
+	err := alg(m.Visitor())
+
+	m.control.Lock()
+	m.flushStep()
+	m.control.Unlock()
+	m.Play()
+
+	return err
+}
+
+// Visitor returns a Visitor that marks discovered nodes as frontier nodes,
+// examined nodes as current, examined edges as visited, finished nodes as
+// visited and selected edges as selected, taking one step per event. Selecting
+// an edge also selects its reverse edge if there is one. Edges carrying flow
+// are marked as visited, or as saturated once the flow reaches their weight,
+// and edges whose flow returns to zero are marked as unvisited. Matched edges
+// and their reverse edges are marked as selected, and as visited once they
+// leave the matching. The nodes of a blossom are marked as contracted until it
+// is expanded, when they are marked as visited. When a tour improves, the
+// edges between consecutive tour nodes in either direction are marked as
+// selected and edges left out of the tour are marked as unvisited again
+func (m *RunManager) Visitor() *Visitor {
+	return &Visitor{
+		DiscoverNode: func(id int) {
+			m.SetNodeState(id, Frontier)
+			m.TakeStep()
+		},
+		ExamineNode: func(id int) {
+			if m.hasCurrent && m.nodeStates[m.current] == Current {
+				m.SetNodeState(m.current, Frontier)
+			}
+			m.current = id
+			m.hasCurrent = true
+			m.SetNodeState(id, Current)
+			m.TakeStep()
+		},
+		ExamineEdge: func(e *structures.Edge) {
+			m.SetEdgeState(KeyOf(e), Visited)
+			m.TakeStep()
+		},
+		FinishNode: func(id int) {
+			m.SetNodeState(id, Visited)
+			m.TakeStep()
+		},
+		SelectEdge: func(e *structures.Edge) {
+			m.SetEdgeState(KeyOf(e), Selected)
+			m.SetEdgeState(KeyOf(e).Reverse(), Selected)
+			m.TakeStep()
+		},
+		AssignFlow: func(e *structures.Edge, flow float64) {
+			switch {
+			case flow >= e.Weight:
+				m.SetEdgeState(KeyOf(e), Saturated)
+			case flow > 0:
+				m.SetEdgeState(KeyOf(e), Visited)
+			default:
+				m.SetEdgeState(KeyOf(e), Unvisited)
+			}
+			m.TakeStep()
+		},
+		MatchEdge: func(e *structures.Edge, matched bool) {
+			s := Visited
+			if matched {
+				s = Selected
+			}
+			m.SetEdgeState(KeyOf(e), s)
+			m.SetEdgeState(KeyOf(e).Reverse(), s)
+			m.TakeStep()
+		},
+		ContractBlossom: func(base int, nodes []int) {
+			for _, id := range nodes {
+				m.SetNodeState(id, Contracted)
+			}
+			m.TakeStep()
+		},
+		ExpandBlossom: func(base int, nodes []int) {
+			for _, id := range nodes {
+				m.SetNodeState(id, Visited)
+			}
+			m.TakeStep()
+		},
+		UpdateTour: func(tour []int) {
+			inTour := make(map[EdgeKey]bool, 2*len(tour))
+			for i, id := range tour {
+				k := EdgeKey{id, tour[(i+1)%len(tour)]}
+				inTour[k] = true
+				inTour[k.Reverse()] = true
+				m.SetNodeState(id, Selected)
+			}
+			for k, s := range m.edgeStates {
+				if s == Selected && !inTour[k] {
+					m.SetEdgeState(k, Unvisited)
+				}
+			}
+			for k := range inTour {
+				m.SetEdgeState(k, Selected)
+			}
+			m.TakeStep()
+		},
+	}
+}
+
+// SetNodeState records a change to the display state of the node with the
+// specified ID. The change is part of the next step
+func (m *RunManager) SetNodeState(id int, s State) {
+	m.control.Lock()
+	defer m.control.Unlock()
+
+	from, ok := m.nodeStates[id]
+	if !ok || from == s {
+		return
+	}
+	m.nodeStates[id] = s
+	m.pending = append(m.pending, stateChange{id: id, from: from, to: s})
+}
+
+// SetEdgeState records a change to the display state of the edge with the
+// specified key. The change is part of the next step
+func (m *RunManager) SetEdgeState(k EdgeKey, s State) {
+	m.control.Lock()
+	defer m.control.Unlock()
+
+	from, ok := m.edgeStates[k]
+	if !ok || from == s {
+		return
+	}
+	m.edgeStates[k] = s
+	m.pending = append(m.pending, stateChange{isEdge: true, key: k, from: from, to: s})
+}
+
+// SelectPath marks the nodes and edges of path p as selected and takes a step
+func (m *RunManager) SelectPath(p *Path) {
+	for _, id := range p.Nodes {
+		m.SetNodeState(id, Selected)
+	}
+	for _, k := range p.Edges() {
+		m.SetEdgeState(k, Selected)
+	}
+	m.TakeStep()
+}
+
+// TakeStep records the state changes since the previous step as one step of
+// the algorithm
+func (m *RunManager) TakeStep() {
+	m.control.Lock()
+	defer m.control.Unlock()
+
+	m.flushStep()
+}
+
+// flushStep is a non-locking version of TakeStep. Steps without changes are not
+// recorded
+func (m *RunManager) flushStep() {
+	if len(m.pending) == 0 {
+		return
+	}
+	m.frames = append(m.frames, m.pending)
+	m.pending = nil
+
+	m.Lock()
+	m.NumSteps = len(m.frames)
+	m.Unlock()
+}
+
+// Play starts stepping forward through the recorded frames, sending one update
+// per step, until the last frame is reached or playback is paused
+func (m *RunManager) Play() {
+	m.control.Lock()
+	defer m.control.Unlock()
+
+	if m.Playing || m.Step == m.NumSteps {
+		return
+	}
+	m.setPlaying(true)
+	m.stop = make(chan struct{})
+	m.stopped = make(chan struct{})
+	go m.play(m.stop, m.stopped)
+}
+
+// play steps forward once per StepDelay until stop is closed or the last frame
+// is reached, then closes stopped
+func (m *RunManager) play(stop, stopped chan struct{}) {
+	defer close(stopped)
+	for {
+		m.control.Lock()
+		delay := m.StepDelay
+		m.control.Unlock()
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(delay):
+		}
+
+		m.control.Lock()
+		select {
+		case <-stop:
+			// Paused while waiting on the lock
+			m.control.Unlock()
+			return
+		default:
+		}
+		moved := m.seek(m.Step + 1)
+		if !moved || m.Step == m.NumSteps {
+			m.setPlaying(false)
+		}
+		playing := m.Playing
+		m.control.Unlock()
+
+		if moved {
+			m.OnUpdate()
+		}
+		if !playing {
+			return
+		}
+	}
+}
+
+// Pause stops playback at the current step and waits for the playback
+// goroutine to exit, so that no update is sent after Pause returns
+func (m *RunManager) Pause() {
+	m.control.Lock()
+	m.pause()
+	stopped := m.stopped
+	m.control.Unlock()
+
+	// The goroutine may need the control lock to see that it was stopped
+	if stopped != nil {
+		<-stopped
+	}
+}
+
+// pause is a non-locking version of Pause that signals the playback goroutine
+// to exit without waiting for it
+func (m *RunManager) pause() {
+	if m.Playing {
+		close(m.stop)
+		m.setPlaying(false)
+	}
+}
+
+// setPlaying sets whether playback is running. The display is locked because
+// the playback state is sent along with the graph
+func (m *RunManager) setPlaying(playing bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.Playing = playing
+}
+
+// StepForward pauses playback and moves forward one step. It returns whether
+// there was a step to move to
+func (m *RunManager) StepForward() bool {
+	m.control.Lock()
+	defer m.control.Unlock()
+
+	m.pause()
+	return m.seek(m.Step + 1)
+}
+
+// StepBack pauses playback and moves back one step. It returns whether there
+// was a step to move to
+func (m *RunManager) StepBack() bool {
+	m.control.Lock()
+	defer m.control.Unlock()
+
+	m.pause()
+	return m.seek(m.Step - 1)
+}
+
+// Seek moves to the requested step, which is clamped to the recorded steps.
+// Playback continues from the new step if it was playing
+func (m *RunManager) Seek(step int) bool {
+	m.control.Lock()
+	defer m.control.Unlock()
+
+	if step < 0 {
+		step = 0
+	} else if step > m.NumSteps {
+		step = m.NumSteps
+	}
+	return m.seek(step)
+}
+
+// seek is a non-locking version of Seek that applies or reverts frames until
+// the requested step is shown. It returns false if the step is out of range
+func (m *RunManager) seek(step int) bool {
+	if step < 0 || step > m.NumSteps || step == m.Step {
+		return false
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	for m.Step < step {
+		for _, c := range m.frames[m.Step] {
+			m.showChange(c, c.to)
+		}
+		m.Step++
+	}
+	for m.Step > step {
+		m.Step--
+		f := m.frames[m.Step]
+		for i := len(f) - 1; i >= 0; i-- {
+			m.showChange(f[i], f[i].from)
+		}
+	}
+	return true
+}
+
+// SetSpeed sets the playback speed in steps per second
+func (m *RunManager) SetSpeed(stepsPerSecond float64) {
+	if stepsPerSecond <= 0 {
+		return
+	}
+
+	m.control.Lock()
+	defer m.control.Unlock()
+
+	m.StepDelay = time.Duration(float64(time.Second) / stepsPerSecond)
+}
+
+// Done stops playback and marks the wrapped display manager as done
+func (m *RunManager) Done() {
+	m.Pause()
+	m.GraphDisplayManager.Done()
+}
+
+// showChange displays state s for the node or edge of change c
+func (m *RunManager) showChange(c stateChange, s State) {
+	if c.isEdge {
+		m.showEdgeState(c.key, s)
+	} else {
+		m.showNodeState(c.id, s)
+	}
+}
+
+// showNodeState colors the node with the specified ID for state s
+func (m *RunManager) showNodeState(id int, s State) {
+	n, err := m.Graph.GetNodeByID(id)
+	if err != nil {
+		return
+	}
+
+	data, ok := structures.ColorDataFromData(n.Extra)
+	if !ok {
+		data = structures.ColorData{Type: structures.DataNodeTag}
+	}
+	data.Color = StateColors[s]
+	n.Extra = data
+}
+
+// showEdgeState colors the edge with the specified key for state s. Unvisited
+// edges are left without display data so that they are drawn with the default
+// style
+func (m *RunManager) showEdgeState(k EdgeKey, s State) {
+	e, err := m.Graph.GetEdgeByNodeID(k.From, k.To)
+	if err != nil {
+		return
+	}
+
+	if s == Unvisited {
+		e.Extra = nil
+		return
+	}
+	e.Extra = structures.ColorData{Color: StateColors[s]}
+}
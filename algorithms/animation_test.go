@@ -0,0 +1,198 @@
+package algorithms
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestRunManager(t *testing.T) {
+	log.Printf("Testing algorithm run manager")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mgr := structures.NewGenericGraphManager(ctx, cancel, 100)
+	for i := 0; i < 4; i++ {
+		mgr.Graph.SetNodeByID(i, float64(i), 0, 0, structures.ColorData{})
+	}
+	mgr.Graph.SetEdgeByNodeID(0, 1, 1, "n", "n", false)
+	mgr.Graph.SetEdgeByNodeID(1, 2, 1, "n", "n", false)
+	// Leave node 3 sized by an earlier display
+	n3, _ := mgr.Graph.GetNodeByID(3)
+	n3.Extra = structures.ColorData{Size: maxScoreSize}
+
+	// Count the updates received by the display
+	updates := make(chan struct{})
+	go func() {
+		for range mgr.Updated() {
+			updates <- struct{}{}
+		}
+		close(updates)
+	}()
+
+	run := NewRunManager(mgr, mgr.Graph)
+	run.StepDelay = 0
+	var states []State
+	err := run.Run("BFS", func(v *Visitor) error {
+		// Record the state of node 1 as each event is observed
+		observed := &Visitor{
+			DiscoverNode: func(id int) {
+				v.DiscoverNode(id)
+				states = append(states, run.nodeStates[1])
+			},
+			ExamineNode: func(id int) {
+				v.ExamineNode(id)
+				states = append(states, run.nodeStates[1])
+			},
+			ExamineEdge: v.ExamineEdge,
+			FinishNode: func(id int) {
+				v.FinishNode(id)
+				states = append(states, run.nodeStates[1])
+			},
+		}
+		_, err := BreadthFirstSearch(mgr.Graph, observed, 0)
+		return err
+	})
+	if err != nil {
+		t.Fatalf(fmt.Sprintf("Could not run algorithm: %v", err))
+	}
+
+	// 3 discoveries, 3 examinations, 2 edges and 3 finishes
+	if run.NumSteps != 11 {
+		t.Fatalf(fmt.Sprintf("Run should take %d steps but took %d", 11, run.NumSteps))
+	}
+	if data, _ := structures.ColorDataFromData(n3.Extra); data.Size != 0 {
+		t.Fatalf(fmt.Sprintf("Run should reset node sizes but node 3 has size %f", data.Size))
+	}
+	want := []State{Unvisited, Unvisited, Frontier, Frontier, Current, Current, Visited, Visited, Visited}
+	if len(states) != len(want) {
+		t.Fatalf(fmt.Sprintf("Node states should be %v but are %v", want, states))
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf(fmt.Sprintf("Node states should be %v but are %v", want, states))
+		}
+	}
+
+	// Playback sends one update per step
+	for i := 0; i < run.NumSteps; i++ {
+		<-updates
+	}
+	run.control.Lock()
+	if run.Playing || run.Step != run.NumSteps {
+		t.Fatalf("Playback should stop at the last step")
+	}
+	run.control.Unlock()
+	checkNodeColor(t, mgr.Graph, 0, Visited)
+	checkNodeColor(t, mgr.Graph, 3, Unvisited)
+	checkEdgeColor(t, mgr.Graph, EdgeKey{0, 1}, Visited)
+
+	// Stepping back replays the state before node 2 was finished
+	if !run.StepBack() {
+		t.Fatalf("Could not step back from the last step")
+	}
+	checkNodeColor(t, mgr.Graph, 2, Current)
+	if !run.StepForward() || run.StepForward() {
+		t.Fatalf("Should step forward to the last step and no further")
+	}
+	checkNodeColor(t, mgr.Graph, 2, Visited)
+
+	// Seeking to the start shows the reset graph
+	run.Seek(-5)
+	if run.Step != 0 {
+		t.Fatalf("Seek should clamp to the first step")
+	}
+	for _, n := range mgr.Graph.Nodes {
+		checkNodeColor(t, mgr.Graph, n.ID, Unvisited)
+	}
+	checkEdgeColor(t, mgr.Graph, EdgeKey{0, 1}, Unvisited)
+	run.Seek(4)
+	checkNodeColor(t, mgr.Graph, 0, Current)
+	checkNodeColor(t, mgr.Graph, 1, Frontier)
+	checkEdgeColor(t, mgr.Graph, EdgeKey{0, 1}, Visited)
+
+	// Playing resumes from the current step at the requested speed
+	run.SetSpeed(1000)
+	if run.StepDelay != time.Millisecond {
+		t.Fatalf(fmt.Sprintf("Step delay should be %v", time.Millisecond))
+	}
+	run.Play()
+	for i := 4; i < run.NumSteps; i++ {
+		<-updates
+	}
+	checkNodeColor(t, mgr.Graph, 2, Visited)
+
+	run.Done()
+	if _, ok := <-updates; ok {
+		t.Fatalf("Display should not receive updates after the run is done")
+	}
+}
+
+func TestRunManagerDone(t *testing.T) {
+	log.Printf("Testing algorithm run manager done during playback")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Finishing the display while playback is sending updates must neither
+	// panic nor send afterwards
+	for i := 0; i < 100; i++ {
+		mgr := structures.NewGenericGraphManager(ctx, cancel, 100)
+		for id := 0; id < 8; id++ {
+			mgr.Graph.SetNodeByID(id, float64(id), 0, 0, structures.ColorData{})
+			mgr.Graph.SetEdgeByNodeID(id, (id+1)%8, 1, "n", "n", false)
+		}
+		updates := make(chan int)
+		go func() {
+			count := 0
+			for range mgr.Updated() {
+				count++
+			}
+			updates <- count
+		}()
+
+		run := NewRunManager(mgr, mgr.Graph)
+		run.StepDelay = 0
+		run.Run("BFS", func(v *Visitor) error {
+			_, err := BreadthFirstSearch(mgr.Graph, v, 0)
+			return err
+		})
+		time.Sleep(time.Duration(i%4) * time.Microsecond)
+		run.Done()
+		<-updates
+
+		run.control.Lock()
+		if run.Playing {
+			t.Fatalf("Playback should stop once the run is done")
+		}
+		run.control.Unlock()
+		mgr.OnUpdate()
+	}
+}
+
+func checkNodeColor(t *testing.T, g *structures.Graph, id int, s State) {
+	t.Helper()
+	n, _ := g.GetNodeByID(id)
+	data, ok := structures.ColorDataFromData(n.Extra)
+	if !ok || data.Color != StateColors[s] {
+		t.Fatalf(fmt.Sprintf("Node %d should have color %s", id, StateColors[s]))
+	}
+}
+
+func checkEdgeColor(t *testing.T, g *structures.Graph, k EdgeKey, s State) {
+	t.Helper()
+	e, _ := g.GetEdgeByNodeID(k.From, k.To)
+	if s == Unvisited {
+		if e.Extra != nil {
+			t.Fatalf(fmt.Sprintf("Edge %v should not have display data", k))
+		}
+		return
+	}
+	data, ok := structures.ColorDataFromData(e.Extra)
+	if !ok || data.Color != StateColors[s] {
+		t.Fatalf(fmt.Sprintf("Edge %v should have color %s", k, StateColors[s]))
+	}
+}
@@ -0,0 +1,197 @@
+package algorithms
+
+import (
+	"fmt"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// NegativeCycleError states that shortest paths are undefined because a cycle
+// of negative total weight is reachable
+// Cycle holds the node IDs of the cycle in edge order. The last node has an
+// edge back to the first
+type NegativeCycleError struct {
+	Cycle []int
+	Err   error
+}
+
+// Error serves the error message for NegativeCycleError
+func (e *NegativeCycleError) Error() string {
+	return fmt.Sprintf("Negative cycle through nodes %v: %v", e.Cycle, e.Err)
+}
+
+func (e *NegativeCycleError) Unwrap() error { return e.Err }
+
+// BellmanFord finds the shortest paths from the source node to every node
+// reachable from it by relaxing every edge once per round until no cost
+// changes. Edge weights may be negative. If a negative cycle is reachable from
+// the source, a *NegativeCycleError holding the cycle is returned. Settled is
+// not set because no cost is final before the last round
+func BellmanFord(g *structures.Graph, v *Visitor, source int) (*ShortestPaths, error) {
+	s, err := g.GetNodeByID(source)
+	if err != nil {
+		return nil, fmt.Errorf("bellman-ford: %w", err)
+	}
+
+	sp := newShortestPaths(s.ID)
+	v.discoverNode(s.ID)
+	if cycle := bellmanFord(g, v, sp.Dist, sp.Parent, nil); cycle != nil {
+		return nil, &NegativeCycleError{cycle, nil}
+	}
+	return sp, nil
+}
+
+// FindNegativeCycle returns a negative cycle anywhere in g, or nil if there is
+// none. It runs Bellman-Ford as if from a virtual source with a zero weight
+// edge to every node
+func FindNegativeCycle(g *structures.Graph) []int {
+	dist, parent := virtualSourceDistances(g)
+	return bellmanFord(g, nil, dist, parent, nil)
+}
+
+// virtualSourceDistances returns the initial costs and parents of a search from
+// a virtual source with a zero weight edge to every node of g
+func virtualSourceDistances(g *structures.Graph) (map[int]float64, map[int]int) {
+	dist := make(map[int]float64, len(g.Nodes))
+	for _, n := range g.Nodes {
+		dist[n.ID] = 0
+	}
+	return dist, make(map[int]int)
+}
+
+// bellmanFord relaxes every edge of g from the reached nodes in dist for up to
+// one round per node. Costs and parents are updated in place. If costs still
+// change after the last round, a negative cycle of the parent graph is
+// returned. If w is nil, stored edge weights are used
+func bellmanFord(g *structures.Graph, v *Visitor, dist map[int]float64, parent map[int]int, w weightFunc) []int {
+	if w == nil {
+		w = func(e *structures.Edge) float64 { return e.Weight }
+	}
+	for round := 0; round < len(g.Nodes); round++ {
+		changed := false
+		for _, n := range g.Nodes {
+			d, ok := dist[n.ID]
+			if !ok {
+				continue
+			}
+			v.examineNode(n.ID)
+			for _, e := range n.Edges {
+				v.examineEdge(e)
+				m := e.Nodes[1].ID
+				if old, ok := dist[m]; !ok || d+w(e) < old {
+					dist[m] = d + w(e)
+					parent[m] = n.ID
+					changed = true
+					if !ok {
+						v.discoverNode(m)
+					}
+				}
+			}
+			v.finishNode(n.ID)
+		}
+
+		if !changed {
+			return nil
+		}
+	}
+
+	// Costs changing after every node has had a round means that some cost
+	// can decrease forever, which only a negative cycle allows
+	return parentCycle(g, parent)
+}
+
+// SPFA finds the shortest paths from the source node to every node reachable
+// from it with the shortest path faster algorithm, a queue based Bellman-Ford
+// that only relaxes the edges of nodes whose cost changed. Edge weights may be
+// negative. If a negative cycle is reachable from the source, a
+// *NegativeCycleError holding the cycle is returned. Settled is not set
+// because no cost is final before the queue empties
+func SPFA(g *structures.Graph, v *Visitor, source int) (*ShortestPaths, error) {
+	s, err := g.GetNodeByID(source)
+	if err != nil {
+		return nil, fmt.Errorf("spfa: %w", err)
+	}
+
+	sp := newShortestPaths(s.ID)
+	queued := map[int]bool{s.ID: true}
+	// Without negative cycles a shortest path has fewer edges than there are
+	// nodes, so longer paths mean a negative cycle
+	edgeCount := map[int]int{s.ID: 0}
+	queue := []*structures.Node{s}
+	v.discoverNode(s.ID)
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		queued[n.ID] = false
+		v.examineNode(n.ID)
+
+		for _, e := range n.Edges {
+			v.examineEdge(e)
+			m := e.Nodes[1].Node
+			d := sp.Dist[n.ID] + e.Weight
+			old, ok := sp.Dist[m.ID]
+			if ok && d >= old {
+				continue
+			}
+
+			sp.Dist[m.ID] = d
+			sp.Parent[m.ID] = n.ID
+			edgeCount[m.ID] = edgeCount[n.ID] + 1
+			if edgeCount[m.ID] >= len(g.Nodes) {
+				// The cycle normally already shows in the parent pointers.
+				// If it does not, further rounds of relaxation expose it
+				cycle := parentCycle(g, sp.Parent)
+				if cycle == nil {
+					cycle = bellmanFord(g, nil, sp.Dist, sp.Parent, nil)
+				}
+				return nil, &NegativeCycleError{cycle, nil}
+			}
+			if !ok {
+				v.discoverNode(m.ID)
+			}
+			if !queued[m.ID] {
+				queued[m.ID] = true
+				queue = append(queue, m)
+			}
+		}
+		v.finishNode(n.ID)
+	}
+
+	return sp, nil
+}
+
+// parentCycle returns a cycle of the graph formed by parent pointers, in edge
+// order, or nil if there is none. A cycle of parent pointers left by
+// Bellman-Ford relaxation always has negative total weight
+func parentCycle(g *structures.Graph, parent map[int]int) []int {
+	// Each node has at most one parent, so following parents from every node
+	// in turn finds each cycle. Nodes walked from an earlier start are marked
+	// with that start so that walks end at the first repeated node
+	walkedFrom := make(map[int]int)
+	for i, n := range g.Nodes {
+		id := n.ID
+		for {
+			if w, ok := walkedFrom[id]; ok {
+				if w != i {
+					break
+				}
+
+				cycle := []int{id}
+				for p := parent[id]; p != id; p = parent[p] {
+					cycle = append(cycle, p)
+				}
+				reverseInts(cycle)
+				return cycle
+			}
+
+			walkedFrom[id] = i
+			p, ok := parent[id]
+			if !ok {
+				break
+			}
+			id = p
+		}
+	}
+	return nil
+}
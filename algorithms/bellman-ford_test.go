@@ -0,0 +1,92 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestBellmanFord(t *testing.T) {
+	log.Printf("Testing Bellman-Ford")
+
+	edges := []mockEdge{
+		{0, 1, 4}, {0, 2, 5}, {1, 3, -3}, {2, 1, -2}, {3, 4, 2}, {2, 4, 4},
+	}
+	want := map[int]float64{0: 0, 1: 3, 2: 5, 3: 0, 4: 2}
+
+	// 1 -> 2 -> 3 -> 1 has weight -1 and is reachable from 0. Node 5 is
+	// unreachable
+	cycleEdges := []mockEdge{
+		{0, 1, 1}, {1, 2, 2}, {2, 3, -4}, {3, 1, 1}, {3, 4, 1}, {5, 0, 1},
+	}
+
+	algs := map[string]func(*structures.Graph, *Visitor, int) (*ShortestPaths, error){
+		"Bellman-Ford": BellmanFord,
+		"SPFA":         SPFA,
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			g := mockGraph(t, 6, edges)
+			sp, err := alg(g, nil, 0)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find shortest paths: %v", err))
+			}
+			checkDistances(t, sp.Dist, want)
+			p, _ := sp.PathTo(4)
+			checkInts(t, "Path to 4", p.Nodes, []int{0, 2, 1, 3, 4})
+
+			// Agrees with Dijkstra on non-negative weights
+			g = mockGraph(t, 5, []mockEdge{{0, 1, 4}, {0, 2, 1}, {2, 1, 2}, {1, 3, 1}, {3, 4, 3}})
+			sp, err = alg(g, nil, 0)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find shortest paths: %v", err))
+			}
+			dsp, _ := Dijkstra(g, nil, 0)
+			checkDistances(t, sp.Dist, dsp.Dist)
+
+			g = mockGraph(t, 6, cycleEdges)
+			_, err = alg(g, nil, 0)
+			var negative *NegativeCycleError
+			if !errors.As(err, &negative) {
+				t.Fatalf("Shortest paths through a negative cycle should fail with NegativeCycleError")
+			}
+			checkNegativeCycle(t, g, negative.Cycle)
+
+			// The cycle is not reachable from node 4
+			if _, err = alg(g, nil, 4); err != nil {
+				t.Fatalf(fmt.Sprintf("Unreachable negative cycles should be ignored: %v", err))
+			}
+		})
+	}
+
+	t.Run("Find negative cycle", func(t *testing.T) {
+		g := mockGraph(t, 6, cycleEdges)
+		checkNegativeCycle(t, g, FindNegativeCycle(g))
+
+		g = mockGraph(t, 6, edges)
+		if cycle := FindNegativeCycle(g); cycle != nil {
+			t.Fatalf(fmt.Sprintf("Graph has no negative cycle but found %v", cycle))
+		}
+	})
+}
+
+func checkNegativeCycle(t *testing.T, g *structures.Graph, cycle []int) {
+	t.Helper()
+	if len(cycle) == 0 {
+		t.Fatalf("Negative cycle should not be empty")
+	}
+	w := 0.0
+	for i, id := range cycle {
+		e, err := g.GetEdgeByNodeID(id, cycle[(i+1)%len(cycle)])
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Cycle %v should follow graph edges", cycle))
+		}
+		w += e.Weight
+	}
+	if w >= 0 {
+		t.Fatalf(fmt.Sprintf("Cycle %v should have negative weight but has %f", cycle, w))
+	}
+}
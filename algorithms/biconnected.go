@@ -0,0 +1,224 @@
+package algorithms
+
+import (
+	"sort"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// Biconnectivity describes the single points of failure of a graph viewed as
+// undirected. An edge and its reverse edge, as created by SetEdge with
+// bidirectional set, are one undirected edge, and edges without a reverse edge
+// are undirected edges too. Self loops are ignored
+type Biconnectivity struct {
+	// ArticulationPoints holds the IDs of the nodes whose removal disconnects
+	// their connected component, in increasing order
+	ArticulationPoints []int
+	// Bridges holds the edges whose removal disconnects their connected
+	// component, with the lower node ID first, in increasing order
+	Bridges []EdgeKey
+	// Blocks holds the node IDs of each biconnected component, the maximal
+	// subgraphs that stay connected after removing any one node. Every edge
+	// belongs to exactly one block, and nodes without edges belong to none
+	Blocks [][]int
+	// TwoEdgeConnected partitions the nodes into the maximal subgraphs that
+	// stay connected after removing any one edge, which are the components
+	// left when every bridge is removed
+	TwoEdgeConnected *Components
+}
+
+// neighbor is an adjacent node of an undirected view of a graph along with an
+// edge that joins them
+type neighbor struct {
+	n *structures.Node
+	e *structures.Edge
+}
+
+// undirectedNeighbors returns the distinct nodes joined to n by an edge in
+// either direction, excluding n itself
+func undirectedNeighbors(g *structures.Graph, n *structures.Node) []neighbor {
+	seen := map[int]bool{n.ID: true}
+	var neighbors []neighbor
+	for _, edges := range [][]*structures.Edge{g.OutEdges(n), g.InEdges(n)} {
+		for _, e := range edges {
+			m := otherNode(e, n.ID)
+			if !seen[m.ID] {
+				seen[m.ID] = true
+				neighbors = append(neighbors, neighbor{m, e})
+			}
+		}
+	}
+	return neighbors
+}
+
+// biconnectedFrame is a node on the depth-first search stack of
+// FindBiconnectivity along with its undirected neighbors and the position of
+// the next one to visit
+type biconnectedFrame struct {
+	n         *structures.Node
+	neighbors []neighbor
+	next      int
+}
+
+// FindBiconnectivity finds the articulation points, bridges, biconnected
+// components and 2-edge-connected components of g viewed as undirected, using
+// the Hopcroft-Tarjan depth-first search. A node's low point is the earliest
+// discovery time reachable from its subtree through one non-tree edge. A child
+// whose low point does not precede its parent's discovery cannot reach above
+// the parent, so the parent separates it from the rest of the graph
+func FindBiconnectivity(g *structures.Graph, v *Visitor) *Biconnectivity {
+	disc := make(map[int]int, len(g.Nodes))
+	low := make(map[int]int, len(g.Nodes))
+	parent := make(map[int]int)
+	articulation := make(map[int]bool)
+	b := &Biconnectivity{}
+	var edges []EdgeKey
+
+	enter := func(n *structures.Node) biconnectedFrame {
+		disc[n.ID] = len(disc)
+		low[n.ID] = disc[n.ID]
+		v.discoverNode(n.ID)
+		v.examineNode(n.ID)
+		return biconnectedFrame{n, undirectedNeighbors(g, n), 0}
+	}
+
+	for _, root := range g.Nodes {
+		if _, ok := disc[root.ID]; ok {
+			continue
+		}
+
+		rootChildren := 0
+		stack := []biconnectedFrame{enter(root)}
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			n := f.n
+			if f.next < len(f.neighbors) {
+				nb := f.neighbors[f.next]
+				f.next++
+				m := nb.n
+				if p, ok := parent[n.ID]; ok && p == m.ID {
+					continue
+				}
+
+				v.examineEdge(nb.e)
+				k := EdgeKey{n.ID, m.ID}
+				if _, ok := disc[m.ID]; !ok {
+					parent[m.ID] = n.ID
+					if n == root {
+						rootChildren++
+					}
+					edges = append(edges, k)
+					stack = append(stack, enter(m))
+				} else if disc[m.ID] < disc[n.ID] {
+					// Back edge to an ancestor
+					edges = append(edges, k)
+					if disc[m.ID] < low[n.ID] {
+						low[n.ID] = disc[m.ID]
+					}
+				}
+				continue
+			}
+
+			v.finishNode(n.ID)
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				break
+			}
+
+			p := stack[len(stack)-1].n
+			v.examineNode(p.ID)
+			if low[n.ID] < low[p.ID] {
+				low[p.ID] = low[n.ID]
+			}
+			if low[n.ID] > disc[p.ID] {
+				b.Bridges = append(b.Bridges, undirectedKeyOf(p.ID, n.ID))
+			}
+			if low[n.ID] >= disc[p.ID] {
+				if p != root {
+					articulation[p.ID] = true
+				}
+
+				// The edges pushed since the tree edge to n form a block
+				tree := EdgeKey{p.ID, n.ID}
+				block := make(map[int]bool)
+				for {
+					k := edges[len(edges)-1]
+					edges = edges[:len(edges)-1]
+					block[k.From] = true
+					block[k.To] = true
+					if k == tree {
+						break
+					}
+				}
+				ids := make([]int, 0, len(block))
+				for id := range block {
+					ids = append(ids, id)
+				}
+				sort.Ints(ids)
+				b.Blocks = append(b.Blocks, ids)
+			}
+		}
+
+		if rootChildren > 1 {
+			articulation[root.ID] = true
+		}
+	}
+
+	for id := range articulation {
+		b.ArticulationPoints = append(b.ArticulationPoints, id)
+	}
+	sort.Ints(b.ArticulationPoints)
+	sort.Slice(b.Bridges, func(i, j int) bool {
+		if b.Bridges[i].From != b.Bridges[j].From {
+			return b.Bridges[i].From < b.Bridges[j].From
+		}
+		return b.Bridges[i].To < b.Bridges[j].To
+	})
+	b.TwoEdgeConnected = twoEdgeConnected(g, b.Bridges)
+	return b
+}
+
+// twoEdgeConnected returns the connected components of g viewed as undirected
+// with the bridges removed. Components are listed in order of their first node
+// in g
+func twoEdgeConnected(g *structures.Graph, bridges []EdgeKey) *Components {
+	isBridge := make(map[EdgeKey]bool, len(bridges))
+	for _, k := range bridges {
+		isBridge[k] = true
+	}
+
+	trees := newUnionFind()
+	for _, n := range g.Nodes {
+		for _, e := range n.Edges {
+			if !isBridge[undirectedKey(e)] {
+				trees.union(e.Nodes[0].ID, e.Nodes[1].ID)
+			}
+		}
+	}
+
+	index := make(map[int]int)
+	var members [][]int
+	for _, n := range g.Nodes {
+		r := trees.find(n.ID)
+		i, ok := index[r]
+		if !ok {
+			i = len(members)
+			index[r] = i
+			members = append(members, nil)
+		}
+		members[i] = append(members[i], n.ID)
+	}
+	return newComponents(members)
+}
+
+// Bridges returns the edges of g viewed as undirected whose removal
+// disconnects their connected component. See FindBiconnectivity
+func Bridges(g *structures.Graph) []EdgeKey {
+	return FindBiconnectivity(g, nil).Bridges
+}
+
+// ArticulationPoints returns the IDs of the nodes of g viewed as undirected
+// whose removal disconnects their connected component. See FindBiconnectivity
+func ArticulationPoints(g *structures.Graph) []int {
+	return FindBiconnectivity(g, nil).ArticulationPoints
+}
@@ -0,0 +1,62 @@
+package algorithms
+
+import (
+	"fmt"
+	"log"
+	"testing"
+)
+
+func TestBiconnectivity(t *testing.T) {
+	log.Printf("Testing biconnectivity")
+
+	// Triangles {0 1 2} and {3 4 5} joined by bridge 1-3, with pendant
+	// bridges 5-6 and 2-10, a separate bridge 7-8 and isolated node 9
+	g := mockUndirectedGraph(t, 11, []mockEdge{
+		{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {1, 3, 1}, {3, 4, 1}, {4, 5, 1},
+		{5, 3, 1}, {5, 6, 1}, {7, 8, 1}, {4, 4, 1},
+	})
+	// Edges without a reverse edge are undirected too
+	if err := g.SetEdgeByNodeID(10, 2, 1, "n", "n", false); err != nil {
+		t.Fatalf(fmt.Sprintf("Could not add edge: %v", err))
+	}
+
+	b := FindBiconnectivity(g, nil)
+	checkInts(t, "Articulation points", b.ArticulationPoints, []int{1, 2, 3, 5})
+
+	wantBridges := []EdgeKey{{1, 3}, {2, 10}, {5, 6}, {7, 8}}
+	if len(b.Bridges) != len(wantBridges) {
+		t.Fatalf(fmt.Sprintf("Bridges should be %v but are %v", wantBridges, b.Bridges))
+	}
+	for i := range wantBridges {
+		if b.Bridges[i] != wantBridges[i] {
+			t.Fatalf(fmt.Sprintf("Bridges should be %v but are %v", wantBridges, b.Bridges))
+		}
+	}
+
+	wantBlocks := [][]int{{0, 1, 2}, {1, 3}, {3, 4, 5}, {5, 6}, {2, 10}, {7, 8}}
+	if len(b.Blocks) != len(wantBlocks) {
+		t.Fatalf(fmt.Sprintf("Blocks should be %v but are %v", wantBlocks, b.Blocks))
+	}
+	for _, want := range wantBlocks {
+		found := false
+		for _, block := range b.Blocks {
+			if fmt.Sprint(block) == fmt.Sprint(want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf(fmt.Sprintf("Blocks %v should include %v", b.Blocks, want))
+		}
+	}
+
+	checkComponents(t, b.TwoEdgeConnected, [][]int{
+		{0, 1, 2}, {3, 4, 5}, {6}, {7}, {8}, {9}, {10},
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		g := mockUndirectedGraph(t, 4, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}})
+		if len(Bridges(g)) != 0 || len(ArticulationPoints(g)) != 0 {
+			t.Fatalf("A cycle has no bridges or articulation points")
+		}
+	})
+}
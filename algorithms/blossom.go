@@ -0,0 +1,872 @@
+package algorithms
+
+import (
+	"math"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// undirectedEdge is an edge of the undirected view of a graph joining the
+// nodes at positions i and j
+type undirectedEdge struct {
+	i, j int
+	e    *structures.Edge
+}
+
+// undirectedEdges returns the edges of g viewed as undirected, with their nodes
+// indexed by position in g, in the order they are first found. Nodes joined in
+// both directions are joined by their heaviest edge, and self loops are left
+// out
+func undirectedEdges(g *structures.Graph) []undirectedEdge {
+	index := make(map[int]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n.ID] = i
+	}
+
+	var edges []undirectedEdge
+	found := make(map[EdgeKey]int)
+	for i, n := range g.Nodes {
+		for _, e := range n.Edges {
+			j := index[e.Nodes[1].ID]
+			if i == j {
+				continue
+			}
+			k := undirectedKeyOf(i, j)
+			if at, ok := found[k]; !ok {
+				found[k] = len(edges)
+				edges = append(edges, undirectedEdge{i, j, e})
+			} else if e.Weight > edges[at].e.Weight {
+				edges[at].e = e
+			}
+		}
+	}
+	return edges
+}
+
+// cyclicAt returns the element of s at position j counted cyclically, so that
+// negative positions count back from the end
+func cyclicAt(s []int, j int) int {
+	return s[(j%len(s)+len(s))%len(s)]
+}
+
+// cardinalityBlossom is the state of Edmonds' blossom algorithm over the nodes
+// of a graph indexed by position
+type cardinalityBlossom struct {
+	g *structures.Graph
+	v *Visitor
+	// adj holds the neighbors of each node and edge the edge joining each pair
+	// of neighbors
+	adj  [][]int
+	edge map[EdgeKey]*structures.Edge
+	// match holds the mate of each node, parent the node before each odd node
+	// of the alternating tree and base the base of the blossom holding each
+	// node, which is the node itself outside blossoms
+	match  []int
+	parent []int
+	base   []int
+	// used marks the even nodes of the alternating tree, and inBlossom marks
+	// the bases of the blossoms on the cycle being contracted
+	used      []bool
+	inBlossom []bool
+}
+
+// Blossom finds a maximum cardinality matching of g viewed as undirected with
+// Edmonds' blossom algorithm. An alternating tree is grown from each free node
+// in search of an augmenting path. When an edge closes an odd cycle of the
+// tree, the cycle is contracted into a blossom that the search treats as one
+// node, and the blossoms are expanded once the search ends. Self loops are
+// ignored
+func Blossom(g *structures.Graph, v *Visitor) *Matching {
+	n := len(g.Nodes)
+	b := &cardinalityBlossom{
+		g:         g,
+		v:         v,
+		adj:       make([][]int, n),
+		edge:      make(map[EdgeKey]*structures.Edge),
+		match:     make([]int, n),
+		parent:    make([]int, n),
+		base:      make([]int, n),
+		used:      make([]bool, n),
+		inBlossom: make([]bool, n),
+	}
+	for _, ue := range undirectedEdges(g) {
+		b.adj[ue.i] = append(b.adj[ue.i], ue.j)
+		b.adj[ue.j] = append(b.adj[ue.j], ue.i)
+		b.edge[undirectedKeyOf(ue.i, ue.j)] = ue.e
+	}
+	for i := range b.match {
+		b.match[i] = -1
+	}
+
+	for root := range g.Nodes {
+		if b.match[root] != -1 {
+			continue
+		}
+
+		// Flip the matching along the augmenting path back to the root
+		for u := b.findPath(root); u != -1; {
+			w := b.parent[u]
+			next := b.match[w]
+			if next != -1 {
+				v.matchEdge(b.edge[undirectedKeyOf(w, next)], false)
+			}
+			b.match[u], b.match[w] = w, u
+			v.matchEdge(b.edge[undirectedKeyOf(u, w)], true)
+			u = next
+		}
+	}
+
+	var edges []*structures.Edge
+	for i, j := range b.match {
+		if i < j {
+			edges = append(edges, b.edge[undirectedKeyOf(i, j)])
+		}
+	}
+	return newMatching(v, edges)
+}
+
+// id returns the node ID of the node at position i
+func (b *cardinalityBlossom) id(i int) int {
+	return b.g.Nodes[i].ID
+}
+
+// lca returns the base of the blossom where the tree paths from x and y meet
+func (b *cardinalityBlossom) lca(x, y int) int {
+	seen := make([]bool, len(b.adj))
+	for {
+		x = b.base[x]
+		seen[x] = true
+		if b.match[x] == -1 {
+			break
+		}
+		x = b.parent[b.match[x]]
+	}
+	for {
+		y = b.base[y]
+		if seen[y] {
+			return y
+		}
+		y = b.parent[b.match[y]]
+	}
+}
+
+// markPath marks the blossoms on the tree path from x up to base and points
+// the odd nodes along it back across the cycle, starting from child
+func (b *cardinalityBlossom) markPath(x, base, child int) {
+	for b.base[x] != base {
+		b.inBlossom[b.base[x]] = true
+		b.inBlossom[b.base[b.match[x]]] = true
+		b.parent[x] = child
+		child = b.match[x]
+		x = b.parent[b.match[x]]
+	}
+}
+
+// findPath grows an alternating tree from root and returns the free node at
+// the end of an augmenting path, or -1 if there is none
+func (b *cardinalityBlossom) findPath(root int) int {
+	for i := range b.adj {
+		b.used[i] = false
+		b.parent[i] = -1
+		b.base[i] = i
+	}
+
+	// Contracted blossoms are expanded in reverse order once the search ends
+	type blossom struct {
+		base  int
+		nodes []int
+	}
+	var contracted []blossom
+	expand := func() {
+		for i := len(contracted) - 1; i >= 0; i-- {
+			b.v.expandBlossom(contracted[i].base, contracted[i].nodes)
+		}
+	}
+
+	b.used[root] = true
+	b.v.discoverNode(b.id(root))
+	queue := []int{root}
+	for len(queue) > 0 {
+		x := queue[0]
+		queue = queue[1:]
+		b.v.examineNode(b.id(x))
+
+		for _, y := range b.adj[x] {
+			if b.base[x] == b.base[y] || b.match[x] == y {
+				continue
+			}
+			b.v.examineEdge(b.edge[undirectedKeyOf(x, y)])
+
+			if y == root || b.match[y] != -1 && b.parent[b.match[y]] != -1 {
+				// x and y are both even, so edge x-y closes an odd cycle
+				base := b.lca(x, y)
+				for i := range b.inBlossom {
+					b.inBlossom[i] = false
+				}
+				b.markPath(x, base, y)
+				b.markPath(y, base, x)
+
+				var nodes []int
+				for i := range b.adj {
+					if !b.inBlossom[b.base[i]] && b.base[i] != base {
+						continue
+					}
+					b.base[i] = base
+					nodes = append(nodes, b.id(i))
+					if !b.used[i] {
+						b.used[i] = true
+						b.v.discoverNode(b.id(i))
+						queue = append(queue, i)
+					}
+				}
+				contracted = append(contracted, blossom{b.id(base), nodes})
+				b.v.contractBlossom(b.id(base), nodes)
+			} else if b.parent[y] == -1 {
+				b.parent[y] = x
+				if b.match[y] == -1 {
+					expand()
+					return y
+				}
+				z := b.match[y]
+				b.used[z] = true
+				b.v.discoverNode(b.id(z))
+				queue = append(queue, z)
+			}
+		}
+		b.v.finishNode(b.id(x))
+	}
+	expand()
+	return -1
+}
+
+// weightedBlossom is the state of the primal-dual blossom algorithm for maximum
+// weight matching over the nodes of a graph indexed by position. Positions
+// from n up to 2n are blossoms that hold nested blossoms and nodes. Edge
+// endpoints are numbered so that endpoint 2k is the first node of edge k and
+// endpoint 2k+1 is the second
+type weightedBlossom struct {
+	g        *structures.Graph
+	v        *Visitor
+	n        int
+	edges    []undirectedEdge
+	endpoint []int
+	// neighbors holds the far endpoint of each edge of each node
+	neighbors [][]int
+	// mate holds the endpoint a node is matched to, or -1 if it is free
+	mate []int
+	// label holds 1 for even, 2 for odd and 0 for unlabeled top-level
+	// blossoms and nodes, and labelEnd the endpoint through which each was
+	// labeled
+	label    []int
+	labelEnd []int
+	// inBlossom holds the top-level blossom of each node, and parent the
+	// blossom immediately holding each node or blossom
+	inBlossom []int
+	parent    []int
+	// children holds the sub-blossoms of each blossom in order around its
+	// cycle starting from the base, and endpoints the endpoints of the edges
+	// joining them
+	children  [][]int
+	endpoints [][]int
+	base      []int
+	// bestEdge holds the least slack edge from each node or blossom to an even
+	// blossom, and bestEdges the candidate edges of each even blossom
+	bestEdge  []int
+	bestEdges [][]int
+	unused    []int
+	dual      []float64
+	allowed   []bool
+	queue     []int
+}
+
+// minWeightPerfectMatching returns the pairs of a lightest perfect matching of
+// an even number of nodes, given by position, where the nodes of each pair are
+// as far apart as d says. It finds the heaviest of the largest matchings of
+// the negated distances
+func minWeightPerfectMatching(nodes []int, d [][]float64) [][2]int {
+	h := structures.NewGraph(math.Inf(1))
+	for _, p := range nodes {
+		h.SetNodeByID(p, 0, 0, 0, nil)
+	}
+	for i, a := range nodes {
+		for _, b := range nodes[i+1:] {
+			h.SetEdgeByNodeID(a, b, -d[a][b], "", "", false)
+		}
+	}
+
+	var pairs [][2]int
+	for _, k := range WeightedBlossom(h, nil, true).Edges {
+		pairs = append(pairs, [2]int{k.From, k.To})
+	}
+	return pairs
+}
+
+// WeightedBlossom finds a matching of greatest total weight of g viewed as
+// undirected with the primal-dual blossom algorithm of Edmonds and Gabow,
+// which keeps dual variables on nodes and blossoms and grows alternating trees
+// along edges of zero slack. If maxCardinality is set, the heaviest of the
+// maximum cardinality matchings is found instead. Nodes joined in both
+// directions are joined by their heaviest edge, and self loops are ignored
+func WeightedBlossom(g *structures.Graph, v *Visitor, maxCardinality bool) *Matching {
+	n := len(g.Nodes)
+	edges := undirectedEdges(g)
+	b := &weightedBlossom{
+		g:         g,
+		v:         v,
+		n:         n,
+		edges:     edges,
+		endpoint:  make([]int, 2*len(edges)),
+		neighbors: make([][]int, n),
+		mate:      make([]int, n),
+		label:     make([]int, 2*n),
+		labelEnd:  make([]int, 2*n),
+		inBlossom: make([]int, n),
+		parent:    make([]int, 2*n),
+		children:  make([][]int, 2*n),
+		endpoints: make([][]int, 2*n),
+		base:      make([]int, 2*n),
+		bestEdge:  make([]int, 2*n),
+		bestEdges: make([][]int, 2*n),
+		dual:      make([]float64, 2*n),
+		allowed:   make([]bool, len(edges)),
+	}
+
+	maxWeight := 0.0
+	for k, ue := range edges {
+		b.endpoint[2*k], b.endpoint[2*k+1] = ue.i, ue.j
+		b.neighbors[ue.i] = append(b.neighbors[ue.i], 2*k+1)
+		b.neighbors[ue.j] = append(b.neighbors[ue.j], 2*k)
+		if ue.e.Weight > maxWeight {
+			maxWeight = ue.e.Weight
+		}
+	}
+	for i := 0; i < 2*n; i++ {
+		b.labelEnd[i] = -1
+		b.parent[i] = -1
+		b.bestEdge[i] = -1
+		if i < n {
+			b.mate[i] = -1
+			b.inBlossom[i] = i
+			b.base[i] = i
+			b.dual[i] = maxWeight
+		} else {
+			b.base[i] = -1
+			b.unused = append(b.unused, i)
+		}
+	}
+
+	// Each stage grows alternating trees from every free node until it finds
+	// an augmenting path or proves that there is none
+	matched := make([]bool, len(edges))
+	for stage := 0; stage < n; stage++ {
+		if !b.stage(maxCardinality) {
+			break
+		}
+
+		joined := make([]bool, len(edges))
+		for k := range matched {
+			m := b.mate[b.edges[k].i] == 2*k+1
+			if matched[k] && !m {
+				v.matchEdge(b.edges[k].e, false)
+			}
+			joined[k] = m && !matched[k]
+			matched[k] = m
+		}
+		for k := range joined {
+			if joined[k] {
+				v.matchEdge(b.edges[k].e, true)
+			}
+		}
+
+		// Blossoms with zero dual variables are expanded between stages
+		for s := n; s < 2*n; s++ {
+			if b.parent[s] == -1 && b.base[s] >= 0 && b.label[s] == 1 && b.dual[s] == 0 {
+				b.expandBlossom(s, true)
+			}
+		}
+	}
+
+	var result []*structures.Edge
+	for k, ue := range edges {
+		if b.mate[ue.i] == 2*k+1 {
+			result = append(result, ue.e)
+		}
+	}
+	return newMatching(v, result)
+}
+
+// slack returns the reduced cost of edge k under the dual variables
+func (b *weightedBlossom) slack(k int) float64 {
+	ue := b.edges[k]
+	return b.dual[ue.i] + b.dual[ue.j] - 2*ue.e.Weight
+}
+
+// leaves returns the nodes held by blossom s, or s itself if it is a node
+func (b *weightedBlossom) leaves(s int) []int {
+	if s < b.n {
+		return []int{s}
+	}
+	var nodes []int
+	for _, t := range b.children[s] {
+		nodes = append(nodes, b.leaves(t)...)
+	}
+	return nodes
+}
+
+// ids returns the node IDs of the nodes held by blossom s
+func (b *weightedBlossom) ids(s int) []int {
+	leaves := b.leaves(s)
+	ids := make([]int, len(leaves))
+	for i, leaf := range leaves {
+		ids[i] = b.g.Nodes[leaf].ID
+	}
+	return ids
+}
+
+// assignLabel labels node w and its top-level blossom with t, reached through
+// endpoint p. The mate of the base of an odd blossom is labeled even
+func (b *weightedBlossom) assignLabel(w, t, p int) {
+	s := b.inBlossom[w]
+	b.label[w], b.label[s] = t, t
+	b.labelEnd[w], b.labelEnd[s] = p, p
+	b.bestEdge[w], b.bestEdge[s] = -1, -1
+	if t == 1 {
+		b.queue = append(b.queue, b.leaves(s)...)
+		b.v.discoverNode(b.g.Nodes[w].ID)
+	} else if t == 2 {
+		base := b.base[s]
+		b.assignLabel(b.endpoint[b.mate[base]], 1, b.mate[base]^1)
+	}
+}
+
+// scanBlossom traces the tree paths back from even nodes x and y and returns
+// the base of the new blossom where they meet, or -1 if they reach different
+// roots, which gives an augmenting path
+func (b *weightedBlossom) scanBlossom(x, y int) int {
+	var path []int
+	base := -1
+	for x != -1 || y != -1 {
+		s := b.inBlossom[x]
+		if b.label[s]&4 != 0 {
+			base = b.base[s]
+			break
+		}
+		path = append(path, s)
+		b.label[s] = 5
+		if b.labelEnd[s] == -1 {
+			// The root of the tree
+			x = -1
+		} else {
+			x = b.endpoint[b.labelEnd[s]]
+			s = b.inBlossom[x]
+			x = b.endpoint[b.labelEnd[s]]
+		}
+		if y != -1 {
+			x, y = y, x
+		}
+	}
+	for _, s := range path {
+		b.label[s] = 1
+	}
+	return base
+}
+
+// addBlossom contracts the odd cycle closed by edge k with the specified base
+// into a new even blossom
+func (b *weightedBlossom) addBlossom(base, k int) {
+	x, y := b.edges[k].i, b.edges[k].j
+	bb, bx, by := b.inBlossom[base], b.inBlossom[x], b.inBlossom[y]
+	s := b.unused[len(b.unused)-1]
+	b.unused = b.unused[:len(b.unused)-1]
+	b.base[s] = base
+	b.parent[s] = -1
+	b.parent[bb] = s
+
+	var path, endps []int
+	for bx != bb {
+		b.parent[bx] = s
+		path = append(path, bx)
+		endps = append(endps, b.labelEnd[bx])
+		x = b.endpoint[b.labelEnd[bx]]
+		bx = b.inBlossom[x]
+	}
+	path = append(path, bb)
+	reverseInts(path)
+	reverseInts(endps)
+	endps = append(endps, 2*k)
+	for by != bb {
+		b.parent[by] = s
+		path = append(path, by)
+		endps = append(endps, b.labelEnd[by]^1)
+		y = b.endpoint[b.labelEnd[by]]
+		by = b.inBlossom[y]
+	}
+	b.children[s] = path
+	b.endpoints[s] = endps
+
+	b.label[s] = 1
+	b.labelEnd[s] = b.labelEnd[bb]
+	b.dual[s] = 0
+	for _, leaf := range b.leaves(s) {
+		if b.label[b.inBlossom[leaf]] == 2 {
+			// Odd nodes become even inside the blossom
+			b.queue = append(b.queue, leaf)
+		}
+		b.inBlossom[leaf] = s
+	}
+
+	// Keep the least slack edge from the blossom to each other even blossom
+	bestTo := make([]int, 2*b.n)
+	for i := range bestTo {
+		bestTo[i] = -1
+	}
+	for _, sub := range path {
+		var lists [][]int
+		if b.bestEdges[sub] == nil {
+			for _, leaf := range b.leaves(sub) {
+				list := make([]int, len(b.neighbors[leaf]))
+				for i, p := range b.neighbors[leaf] {
+					list[i] = p / 2
+				}
+				lists = append(lists, list)
+			}
+		} else {
+			lists = [][]int{b.bestEdges[sub]}
+		}
+		for _, list := range lists {
+			for _, e := range list {
+				j := b.edges[e].j
+				if b.inBlossom[j] == s {
+					j = b.edges[e].i
+				}
+				bj := b.inBlossom[j]
+				if bj != s && b.label[bj] == 1 && (bestTo[bj] == -1 || b.slack(e) < b.slack(bestTo[bj])) {
+					bestTo[bj] = e
+				}
+			}
+		}
+		b.bestEdges[sub] = nil
+		b.bestEdge[sub] = -1
+	}
+	b.bestEdges[s] = []int{}
+	for _, e := range bestTo {
+		if e != -1 {
+			b.bestEdges[s] = append(b.bestEdges[s], e)
+		}
+	}
+	b.bestEdge[s] = -1
+	for _, e := range b.bestEdges[s] {
+		if b.bestEdge[s] == -1 || b.slack(e) < b.slack(b.bestEdge[s]) {
+			b.bestEdge[s] = e
+		}
+	}
+
+	b.v.contractBlossom(b.g.Nodes[base].ID, b.ids(s))
+}
+
+// expandBlossom turns the sub-blossoms of top-level blossom s into top-level
+// blossoms. Between stages, sub-blossoms with zero dual variables are expanded
+// too. Within a stage, the sub-blossoms of an odd blossom are relabeled so that
+// the alternating tree stays intact
+func (b *weightedBlossom) expandBlossom(s int, endStage bool) {
+	b.v.expandBlossom(b.g.Nodes[b.base[s]].ID, b.ids(s))
+	for _, sub := range b.children[s] {
+		b.parent[sub] = -1
+		if sub < b.n {
+			b.inBlossom[sub] = sub
+		} else if endStage && b.dual[sub] == 0 {
+			b.expandBlossom(sub, endStage)
+		} else {
+			for _, leaf := range b.leaves(sub) {
+				b.inBlossom[leaf] = sub
+			}
+		}
+	}
+
+	if !endStage && b.label[s] == 2 {
+		// Relabel the even length path around the cycle from the sub-blossom
+		// the blossom was entered through to its base
+		children, endps := b.children[s], b.endpoints[s]
+		entry := b.inBlossom[b.endpoint[b.labelEnd[s]^1]]
+		j := indexOf(children, entry)
+		step, trick := -1, 1
+		if j&1 != 0 {
+			j -= len(children)
+			step, trick = 1, 0
+		}
+		p := b.labelEnd[s]
+		for j != 0 {
+			b.label[b.endpoint[p^1]] = 0
+			b.label[b.endpoint[cyclicAt(endps, j-trick)^trick^1]] = 0
+			b.assignLabel(b.endpoint[p^1], 2, p)
+			b.allowed[cyclicAt(endps, j-trick)/2] = true
+			j += step
+			p = cyclicAt(endps, j-trick) ^ trick
+			b.allowed[p/2] = true
+			j += step
+		}
+		sub := cyclicAt(children, j)
+		b.label[b.endpoint[p^1]], b.label[sub] = 2, 2
+		b.labelEnd[b.endpoint[p^1]], b.labelEnd[sub] = p, p
+		b.bestEdge[sub] = -1
+		j += step
+
+		// Sub-blossoms off the path are unlabeled unless a node inside was
+		// reached from outside, in which case it is labeled odd
+		for cyclicAt(children, j) != entry {
+			sub := cyclicAt(children, j)
+			j += step
+			if b.label[sub] == 1 {
+				continue
+			}
+			for _, leaf := range b.leaves(sub) {
+				if b.label[leaf] != 0 {
+					b.label[leaf] = 0
+					b.label[b.endpoint[b.mate[b.base[sub]]]] = 0
+					b.assignLabel(leaf, 2, b.labelEnd[leaf])
+					break
+				}
+			}
+		}
+	}
+
+	b.label[s], b.labelEnd[s] = -1, -1
+	b.children[s], b.endpoints[s] = nil, nil
+	b.base[s] = -1
+	b.bestEdges[s] = nil
+	b.bestEdge[s] = -1
+	b.unused = append(b.unused, s)
+}
+
+// augmentBlossom flips the matching inside blossom s along the even length
+// path from node x to the base, making x the new base
+func (b *weightedBlossom) augmentBlossom(s, x int) {
+	t := x
+	for b.parent[t] != s {
+		t = b.parent[t]
+	}
+	if t >= b.n {
+		b.augmentBlossom(t, x)
+	}
+
+	i := indexOf(b.children[s], t)
+	j := i
+	step, trick := -1, 1
+	if i&1 != 0 {
+		j -= len(b.children[s])
+		step, trick = 1, 0
+	}
+	for j != 0 {
+		j += step
+		t = cyclicAt(b.children[s], j)
+		p := cyclicAt(b.endpoints[s], j-trick) ^ trick
+		if t >= b.n {
+			b.augmentBlossom(t, b.endpoint[p])
+		}
+		j += step
+		t = cyclicAt(b.children[s], j)
+		if t >= b.n {
+			b.augmentBlossom(t, b.endpoint[p^1])
+		}
+		b.mate[b.endpoint[p]] = p ^ 1
+		b.mate[b.endpoint[p^1]] = p
+	}
+
+	// Rotate the cycle so that it starts from the new base
+	b.children[s] = append(append([]int{}, b.children[s][i:]...), b.children[s][:i]...)
+	b.endpoints[s] = append(append([]int{}, b.endpoints[s][i:]...), b.endpoints[s][:i]...)
+	b.base[s] = b.base[b.children[s][0]]
+}
+
+// augmentMatching flips the matching along the augmenting path through edge k
+// between the roots of two alternating trees
+func (b *weightedBlossom) augmentMatching(k int) {
+	for _, sp := range [][2]int{{b.edges[k].i, 2*k + 1}, {b.edges[k].j, 2 * k}} {
+		s, p := sp[0], sp[1]
+		for {
+			bs := b.inBlossom[s]
+			if bs >= b.n {
+				b.augmentBlossom(bs, s)
+			}
+			b.mate[s] = p
+			if b.labelEnd[bs] == -1 {
+				// The root of the tree
+				break
+			}
+			t := b.endpoint[b.labelEnd[bs]]
+			bt := b.inBlossom[t]
+			s = b.endpoint[b.labelEnd[bt]]
+			j := b.endpoint[b.labelEnd[bt]^1]
+			if bt >= b.n {
+				b.augmentBlossom(bt, j)
+			}
+			b.mate[j] = b.labelEnd[bt]
+			p = b.labelEnd[bt] ^ 1
+		}
+	}
+}
+
+// stage grows alternating trees from every free node, adjusting the dual
+// variables whenever no edge of zero slack is left to follow. It returns
+// whether the matching was augmented
+func (b *weightedBlossom) stage(maxCardinality bool) bool {
+	for i := range b.label {
+		b.label[i] = 0
+		b.bestEdge[i] = -1
+		if i >= b.n {
+			b.bestEdges[i] = nil
+		}
+	}
+	for k := range b.allowed {
+		b.allowed[k] = false
+	}
+	b.queue = b.queue[:0]
+	for x := 0; x < b.n; x++ {
+		if b.mate[x] == -1 && b.label[b.inBlossom[x]] == 0 {
+			b.assignLabel(x, 1, -1)
+		}
+	}
+
+	for {
+		for len(b.queue) > 0 {
+			x := b.queue[len(b.queue)-1]
+			b.queue = b.queue[:len(b.queue)-1]
+			b.v.examineNode(b.g.Nodes[x].ID)
+
+			for _, p := range b.neighbors[x] {
+				k := p / 2
+				y := b.endpoint[p]
+				if b.inBlossom[x] == b.inBlossom[y] {
+					continue
+				}
+				b.v.examineEdge(b.edges[k].e)
+
+				var slack float64
+				if !b.allowed[k] {
+					slack = b.slack(k)
+					if slack <= 0 {
+						b.allowed[k] = true
+					}
+				}
+				switch {
+				case b.allowed[k] && b.label[b.inBlossom[y]] == 0:
+					b.assignLabel(y, 2, p^1)
+				case b.allowed[k] && b.label[b.inBlossom[y]] == 1:
+					if base := b.scanBlossom(x, y); base >= 0 {
+						b.addBlossom(base, k)
+					} else {
+						b.augmentMatching(k)
+						return true
+					}
+				case b.allowed[k] && b.label[y] == 0:
+					// y is in an odd blossom but not yet reached itself
+					b.label[y] = 2
+					b.labelEnd[y] = p ^ 1
+				case !b.allowed[k] && b.label[b.inBlossom[y]] == 1:
+					s := b.inBlossom[x]
+					if b.bestEdge[s] == -1 || slack < b.slack(b.bestEdge[s]) {
+						b.bestEdge[s] = k
+					}
+				case !b.allowed[k] && b.label[y] == 0:
+					if b.bestEdge[y] == -1 || slack < b.slack(b.bestEdge[y]) {
+						b.bestEdge[y] = k
+					}
+				}
+			}
+			b.v.finishNode(b.g.Nodes[x].ID)
+		}
+
+		// Find the least change of dual variables that makes a new edge
+		// usable, empties an odd blossom or, unless the matching must have
+		// maximum cardinality, ends the stage
+		deltaType, delta, deltaEdge, deltaBlossom := -1, 0.0, -1, -1
+		if !maxCardinality {
+			deltaType, delta = 1, b.minNodeDual()
+		}
+		for x := 0; x < b.n; x++ {
+			if b.label[b.inBlossom[x]] == 0 && b.bestEdge[x] != -1 {
+				if d := b.slack(b.bestEdge[x]); deltaType == -1 || d < delta {
+					deltaType, delta, deltaEdge = 2, d, b.bestEdge[x]
+				}
+			}
+		}
+		for s := 0; s < 2*b.n; s++ {
+			if b.parent[s] == -1 && b.label[s] == 1 && b.bestEdge[s] != -1 {
+				if d := b.slack(b.bestEdge[s]) / 2; deltaType == -1 || d < delta {
+					deltaType, delta, deltaEdge = 3, d, b.bestEdge[s]
+				}
+			}
+		}
+		for s := b.n; s < 2*b.n; s++ {
+			if b.base[s] >= 0 && b.parent[s] == -1 && b.label[s] == 2 &&
+				(deltaType == -1 || b.dual[s] < delta) {
+				deltaType, delta, deltaBlossom = 4, b.dual[s], s
+			}
+		}
+		if deltaType == -1 {
+			// No further improvement is possible, so the dual variables are
+			// made optimal for a maximum weight matching
+			deltaType = 1
+			if delta = b.minNodeDual(); delta < 0 {
+				delta = 0
+			}
+		}
+
+		for x := 0; x < b.n; x++ {
+			switch b.label[b.inBlossom[x]] {
+			case 1:
+				b.dual[x] -= delta
+			case 2:
+				b.dual[x] += delta
+			}
+		}
+		for s := b.n; s < 2*b.n; s++ {
+			if b.base[s] >= 0 && b.parent[s] == -1 {
+				switch b.label[s] {
+				case 1:
+					b.dual[s] += delta
+				case 2:
+					b.dual[s] -= delta
+				}
+			}
+		}
+
+		switch deltaType {
+		case 1:
+			return false
+		case 2:
+			b.allowed[deltaEdge] = true
+			i, j := b.edges[deltaEdge].i, b.edges[deltaEdge].j
+			if b.label[b.inBlossom[i]] == 0 {
+				i = j
+			}
+			b.queue = append(b.queue, i)
+		case 3:
+			b.allowed[deltaEdge] = true
+			b.queue = append(b.queue, b.edges[deltaEdge].i)
+		case 4:
+			b.expandBlossom(deltaBlossom, false)
+		}
+	}
+}
+
+// minNodeDual returns the least dual variable of any node
+func (b *weightedBlossom) minNodeDual() float64 {
+	d := b.dual[0]
+	for x := 1; x < b.n; x++ {
+		if b.dual[x] < d {
+			d = b.dual[x]
+		}
+	}
+	return d
+}
+
+// indexOf returns the position of x in s, or -1 if it is not there
+func indexOf(s []int, x int) int {
+	for i, y := range s {
+		if y == x {
+			return i
+		}
+	}
+	return -1
+}
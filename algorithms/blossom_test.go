@@ -0,0 +1,176 @@
+package algorithms
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestBlossom(t *testing.T) {
+	log.Printf("Testing blossom matching")
+
+	t.Run("Cardinality", func(t *testing.T) {
+		// Matching 0-1 first leaves the search from 2 to contract triangle
+		// 0 1 2 before it reaches free node 3 through node 1
+		g := mockUndirectedGraph(t, 4, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {1, 3, 1}})
+		var contracted, expanded [][]int
+		v := &Visitor{
+			ContractBlossom: func(base int, nodes []int) {
+				contracted = append(contracted, nodes)
+			},
+			ExpandBlossom: func(base int, nodes []int) {
+				expanded = append(expanded, nodes)
+			},
+		}
+		m := Blossom(g, v)
+		checkMatching(t, g, m, 2)
+		if m.Mate[1] != 3 || m.Mate[0] != 2 {
+			t.Fatalf(fmt.Sprintf("Matching should be 0-2 and 1-3 but is %v", m.Edges))
+		}
+		if len(contracted) != 1 || len(expanded) != 1 {
+			t.Fatalf(fmt.Sprintf("One blossom should be contracted and expanded but %v and %v were", contracted, expanded))
+		}
+		checkInts(t, "Blossom", contracted[0], []int{0, 1, 2})
+
+		// A 5-cycle with a pendant node has a perfect matching
+		g = mockUndirectedGraph(t, 6, []mockEdge{
+			{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 0, 1}, {2, 5, 1},
+		})
+		checkMatching(t, g, Blossom(g, nil), 3)
+	})
+
+	t.Run("Weighted", func(t *testing.T) {
+		cases := []struct {
+			name           string
+			n              int
+			edges          []mockEdge
+			maxCardinality bool
+			size           int
+			weight         float64
+		}{
+			{"Single edge", 3, []mockEdge{{0, 1, 10}, {1, 2, 11}}, false, 1, 11},
+			{"Lighter", 4, []mockEdge{{0, 1, 5}, {1, 2, 11}, {2, 3, 5}}, false, 1, 11},
+			{"Larger", 4, []mockEdge{{0, 1, 5}, {1, 2, 11}, {2, 3, 5}}, true, 2, 10},
+			{"Negative", 4, []mockEdge{{0, 1, 2}, {0, 2, -2}, {1, 2, 1}, {1, 3, -1}, {2, 3, -6}}, false, 1, 2},
+			{"Negative larger", 4, []mockEdge{{0, 1, 2}, {0, 2, -2}, {1, 2, 1}, {1, 3, -1}, {2, 3, -6}}, true, 2, -3},
+			{"Blossom", 4, []mockEdge{{0, 1, 8}, {0, 2, 9}, {1, 2, 10}, {2, 3, 7}}, false, 2, 15},
+			{"Blossom with stems", 6, []mockEdge{
+				{0, 1, 8}, {0, 2, 9}, {1, 2, 10}, {2, 3, 7}, {0, 5, 5}, {3, 4, 6},
+			}, false, 3, 21},
+			{"Even blossom relabeled odd", 6, []mockEdge{
+				{0, 1, 9}, {0, 2, 8}, {1, 2, 10}, {0, 3, 5}, {3, 4, 4}, {0, 5, 3},
+			}, false, 3, 17},
+			{"Nested blossom expanded", 10, []mockEdge{
+				{0, 1, 45}, {0, 4, 45}, {1, 2, 50}, {2, 3, 45}, {3, 4, 50},
+				{0, 5, 30}, {2, 8, 35}, {3, 7, 35}, {4, 6, 26}, {8, 9, 5},
+			}, false, 5, 146},
+		}
+		for _, c := range cases {
+			g := mockUndirectedGraph(t, c.n, c.edges)
+			m := WeightedBlossom(g, nil, c.maxCardinality)
+			checkMatching(t, g, m, c.size)
+			if m.Weight != c.weight {
+				t.Fatalf(fmt.Sprintf("%s matching should weigh %f but weighs %f", c.name, c.weight, m.Weight))
+			}
+		}
+	})
+
+	t.Run("Random graphs", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 200; i++ {
+			n := 2 + r.Intn(7)
+			var edges []mockEdge
+			for a := 0; a < n; a++ {
+				for b := a + 1; b < n; b++ {
+					if r.Intn(2) == 0 {
+						edges = append(edges, mockEdge{a, b, float64(r.Intn(20) - 4)})
+					}
+				}
+			}
+			g := mockUndirectedGraph(t, n, edges)
+
+			size, _ := bruteForceMatching(edges, 0, map[int]bool{}, true)
+			if m := Blossom(g, nil); len(m.Edges) != size {
+				t.Fatalf(fmt.Sprintf("Blossom matching of %v should have %d edges but is %v", edges, size, m.Edges))
+			}
+			for _, maxCardinality := range []bool{false, true} {
+				want, weight := bruteForceMatching(edges, 0, map[int]bool{}, maxCardinality)
+				m := WeightedBlossom(g, nil, maxCardinality)
+				checkMatching(t, g, m, len(m.Edges))
+				if m.Weight != weight || maxCardinality && len(m.Edges) != want {
+					t.Fatalf(fmt.Sprintf(
+						"Weighted matching of %v with maximum cardinality %t should weigh %f but is %v",
+						edges, maxCardinality, weight, m.Edges,
+					))
+				}
+			}
+		}
+	})
+}
+
+// bruteForceMatching returns the size and weight of the best matching of the
+// edges from position i on that avoids the used nodes. The best matching is
+// the heaviest, or the heaviest of the largest if maxCardinality is set
+func bruteForceMatching(edges []mockEdge, i int, used map[int]bool, maxCardinality bool) (int, float64) {
+	if i == len(edges) {
+		return 0, 0
+	}
+	size, weight := bruteForceMatching(edges, i+1, used, maxCardinality)
+	e := edges[i]
+	if used[e.from] || used[e.to] {
+		return size, weight
+	}
+
+	used[e.from], used[e.to] = true, true
+	s, w := bruteForceMatching(edges, i+1, used, maxCardinality)
+	used[e.from], used[e.to] = false, false
+	s, w = s+1, w+e.w
+	if maxCardinality && s != size {
+		if s > size {
+			return s, w
+		}
+		return size, weight
+	}
+	if w > weight {
+		return s, w
+	}
+	return size, weight
+}
+
+// Matched edges are reported to the visitor as the matching changes
+func TestBlossomVisitor(t *testing.T) {
+	log.Printf("Testing blossom matching visitor")
+
+	g := mockUndirectedGraph(t, 10, []mockEdge{
+		{0, 1, 45}, {0, 4, 45}, {1, 2, 50}, {2, 3, 45}, {3, 4, 50},
+		{0, 5, 30}, {2, 8, 35}, {3, 7, 35}, {4, 6, 26}, {8, 9, 5},
+	})
+	algs := map[string]func(*structures.Graph, *Visitor) *Matching{
+		"Cardinality": Blossom,
+		"Weighted": func(g *structures.Graph, v *Visitor) *Matching {
+			return WeightedBlossom(g, v, false)
+		},
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			matched := make(map[EdgeKey]bool)
+			v := &Visitor{MatchEdge: func(e *structures.Edge, m bool) {
+				matched[undirectedKey(e)] = m
+			}}
+			m := alg(g, v)
+			for k, in := range matched {
+				if _, ok := m.Mate[k.From]; in && (!ok || m.Mate[k.From] != k.To) {
+					t.Fatalf(fmt.Sprintf("Edge %v should not be reported as matched", k))
+				}
+			}
+			for _, k := range m.Edges {
+				if !matched[undirectedKeyOf(k.From, k.To)] {
+					t.Fatalf(fmt.Sprintf("Edge %v should be reported as matched", k))
+				}
+			}
+		})
+	}
+}
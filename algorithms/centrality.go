@@ -0,0 +1,472 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+const (
+	// centralityIterations is the most iterations an iterative centrality
+	// measure runs before giving up on converging
+	centralityIterations = 1000
+	// centralityTolerance is the largest change of score per node between
+	// iterations at which an iterative centrality measure has converged
+	centralityTolerance = 1e-10
+
+	// minScoreSize and maxScoreSize are the display sizes of the lowest and
+	// highest scoring nodes shown by ShowScores
+	minScoreSize = 1
+	maxScoreSize = 3
+)
+
+// ConvergenceError states that an iterative algorithm did not converge
+type ConvergenceError struct {
+	iterations int
+	Err        error
+}
+
+// Error serves the error message for ConvergenceError
+func (e *ConvergenceError) Error() string {
+	return fmt.Sprintf("No convergence after %d iterations: %v", e.iterations, e.Err)
+}
+
+func (e *ConvergenceError) Unwrap() error { return e.Err }
+
+// normalizer returns the factor that scales a count over the other nodes of g
+// into the range 0 to 1
+func normalizer(g *structures.Graph) float64 {
+	if len(g.Nodes) <= 1 {
+		return 1
+	}
+	return 1 / float64(len(g.Nodes)-1)
+}
+
+// DegreeCentrality scores each node of g by the fraction of the other nodes
+// joined to it by an edge in either direction
+func DegreeCentrality(g *structures.Graph) map[int]float64 {
+	s := normalizer(g)
+	scores := make(map[int]float64, len(g.Nodes))
+	for _, n := range g.Nodes {
+		scores[n.ID] = float64(len(undirectedNeighbors(g, n))) * s
+	}
+	return scores
+}
+
+// InDegreeCentrality scores each node of g by its number of incoming edges
+// over the number of other nodes
+func InDegreeCentrality(g *structures.Graph) map[int]float64 {
+	s := normalizer(g)
+	scores := make(map[int]float64, len(g.Nodes))
+	for _, n := range g.Nodes {
+		scores[n.ID] = float64(len(g.InEdges(n))) * s
+	}
+	return scores
+}
+
+// OutDegreeCentrality scores each node of g by its number of outgoing edges
+// over the number of other nodes
+func OutDegreeCentrality(g *structures.Graph) map[int]float64 {
+	s := normalizer(g)
+	scores := make(map[int]float64, len(g.Nodes))
+	for _, n := range g.Nodes {
+		scores[n.ID] = float64(len(n.Edges)) * s
+	}
+	return scores
+}
+
+// checkWeights returns a *NegativeWeightError if any edge of g has a negative
+// weight
+func checkWeights(g *structures.Graph) error {
+	for _, n := range g.Nodes {
+		for _, e := range n.Edges {
+			if e.Weight < 0 {
+				return &NegativeWeightError{KeyOf(e), e.Weight, nil}
+			}
+		}
+	}
+	return nil
+}
+
+// shortestPathDAG holds every shortest path from a source node, as needed by
+// the distance based centrality measures
+type shortestPathDAG struct {
+	// dist holds the cost of the shortest paths to each reachable node
+	dist map[int]float64
+	// sigma holds the number of shortest paths to each reachable node, and
+	// pred the nodes before it on those paths
+	sigma map[int]float64
+	pred  map[int][]int
+	// order holds the reachable nodes in order of increasing cost
+	order []int
+}
+
+// newShortestPathDAG finds every shortest path from source in g by
+// breadth-first search, or by Dijkstra's algorithm over edge weights if
+// weighted is set. Weights must not be negative
+func newShortestPathDAG(g *structures.Graph, source *structures.Node, weighted bool) *shortestPathDAG {
+	d := &shortestPathDAG{
+		dist:  map[int]float64{source.ID: 0},
+		sigma: map[int]float64{source.ID: 1},
+		pred:  make(map[int][]int),
+	}
+	w := func(e *structures.Edge) float64 { return 1 }
+	if weighted {
+		w = func(e *structures.Edge) float64 { return e.Weight }
+	}
+
+	settled := make(map[int]bool)
+	q := &priorityQueue{}
+	q.push(source.ID, 0)
+	for q.Len() > 0 {
+		id := q.pop().id
+		if settled[id] {
+			continue
+		}
+		settled[id] = true
+		d.order = append(d.order, id)
+
+		n, _ := g.GetNodeByID(id)
+		for _, e := range n.Edges {
+			m := e.Nodes[1].ID
+			if settled[m] {
+				continue
+			}
+			cost := d.dist[id] + w(e)
+			if old, ok := d.dist[m]; !ok || cost < old {
+				d.dist[m] = cost
+				d.sigma[m] = d.sigma[id]
+				d.pred[m] = []int{id}
+				q.push(m, cost)
+			} else if cost == old {
+				d.sigma[m] += d.sigma[id]
+				d.pred[m] = append(d.pred[m], id)
+			}
+		}
+	}
+	return d
+}
+
+// distanceCentrality scores each node of g by applying score to its shortest
+// path distances to the other nodes it reaches
+func distanceCentrality(g *structures.Graph, weighted bool, score func(dist map[int]float64) float64) (map[int]float64, error) {
+	if weighted {
+		if err := checkWeights(g); err != nil {
+			return nil, err
+		}
+	}
+
+	scores := make(map[int]float64, len(g.Nodes))
+	for _, n := range g.Nodes {
+		scores[n.ID] = score(newShortestPathDAG(g, n, weighted).dist)
+	}
+	return scores, nil
+}
+
+// ClosenessCentrality scores each node of g by the inverse of the average cost
+// of its shortest paths to the nodes it reaches, scaled by the fraction of
+// other nodes it reaches so that nodes reaching few others score low. Paths
+// cost their number of edges, or the sum of their edge weights if weighted is
+// set, in which case a negative weight gives a *NegativeWeightError
+func ClosenessCentrality(g *structures.Graph, weighted bool) (map[int]float64, error) {
+	s := normalizer(g)
+	scores, err := distanceCentrality(g, weighted, func(dist map[int]float64) float64 {
+		total := 0.0
+		for _, d := range dist {
+			total += d
+		}
+		if total == 0 {
+			return 0
+		}
+		reached := float64(len(dist) - 1)
+		return reached / total * reached * s
+	})
+	if err != nil {
+		return nil, fmt.Errorf("closeness centrality: %w", err)
+	}
+	return scores, nil
+}
+
+// HarmonicCentrality scores each node of g by the sum of the inverse costs of
+// its shortest paths to the other nodes over the number of other nodes, where
+// unreachable nodes and nodes at no cost add nothing. Paths cost their number
+// of edges, or the sum of their edge weights if weighted is set, in which case
+// a negative weight gives a *NegativeWeightError
+func HarmonicCentrality(g *structures.Graph, weighted bool) (map[int]float64, error) {
+	s := normalizer(g)
+	scores, err := distanceCentrality(g, weighted, func(dist map[int]float64) float64 {
+		total := 0.0
+		for _, d := range dist {
+			if d > 0 {
+				total += 1 / d
+			}
+		}
+		return total * s
+	})
+	if err != nil {
+		return nil, fmt.Errorf("harmonic centrality: %w", err)
+	}
+	return scores, nil
+}
+
+// BetweennessCentrality scores each node of g by the fraction of shortest
+// paths between other pairs of nodes that pass through it, using Brandes'
+// algorithm, which accumulates the dependencies of each source on the other
+// nodes back along its shortest paths. Scores are scaled by the number of
+// ordered pairs of other nodes, so that they range from 0 to 1. Paths cost
+// their number of edges, or the sum of their edge weights if weighted is set,
+// in which case a negative weight gives a *NegativeWeightError
+func BetweennessCentrality(g *structures.Graph, weighted bool) (map[int]float64, error) {
+	if weighted {
+		if err := checkWeights(g); err != nil {
+			return nil, fmt.Errorf("betweenness centrality: %w", err)
+		}
+	}
+
+	scores := make(map[int]float64, len(g.Nodes))
+	for _, n := range g.Nodes {
+		scores[n.ID] = 0
+	}
+	for _, n := range g.Nodes {
+		d := newShortestPathDAG(g, n, weighted)
+		delta := make(map[int]float64, len(d.order))
+		for i := len(d.order) - 1; i > 0; i-- {
+			w := d.order[i]
+			for _, u := range d.pred[w] {
+				delta[u] += d.sigma[u] / d.sigma[w] * (1 + delta[w])
+			}
+			scores[w] += delta[w]
+		}
+	}
+
+	if n := len(g.Nodes); n > 2 {
+		for id := range scores {
+			scores[id] /= float64((n - 1) * (n - 2))
+		}
+	}
+	return scores, nil
+}
+
+// iterateScores repeatedly replaces the scores x with next(x) until no score
+// changes by more than centralityTolerance
+func iterateScores(x []float64, next func(x []float64) []float64) ([]float64, error) {
+	for i := 0; i < centralityIterations; i++ {
+		y := next(x)
+		change := 0.0
+		for j := range x {
+			change = math.Max(change, math.Abs(y[j]-x[j]))
+		}
+		x = y
+		if change <= centralityTolerance {
+			return x, nil
+		}
+		if math.IsInf(change, 0) || math.IsNaN(change) {
+			break
+		}
+	}
+	return nil, &ConvergenceError{centralityIterations, nil}
+}
+
+// scoreMap returns the scores x, indexed by node position in g, keyed by node
+// ID. If unit is set, the scores are scaled to unit Euclidean length
+func scoreMap(g *structures.Graph, x []float64, unit bool) map[int]float64 {
+	s := 1.0
+	if unit {
+		length := 0.0
+		for _, xi := range x {
+			length += xi * xi
+		}
+		if length > 0 {
+			s = 1 / math.Sqrt(length)
+		}
+	}
+
+	scores := make(map[int]float64, len(x))
+	for i, n := range g.Nodes {
+		scores[n.ID] = x[i] * s
+	}
+	return scores
+}
+
+// inEdges returns, for each node position of g, the positions of the near
+// nodes of its incoming edges alongside the edges
+func inEdges(g *structures.Graph) ([][]int, [][]*structures.Edge) {
+	index := make(map[int]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n.ID] = i
+	}
+	from := make([][]int, len(g.Nodes))
+	edges := make([][]*structures.Edge, len(g.Nodes))
+	for i, n := range g.Nodes {
+		for _, e := range n.Edges {
+			j := index[e.Nodes[1].ID]
+			from[j] = append(from[j], i)
+			edges[j] = append(edges[j], e)
+		}
+	}
+	return from, edges
+}
+
+// EigenvectorCentrality scores each node of g by the sum of the scores of the
+// nodes with edges into it, weighted by edge weight, so that a node is central
+// if central nodes point to it. The scores are the principal eigenvector of
+// the weighted adjacency matrix, found by power iteration and scaled to unit
+// length. Each iteration also keeps the previous scores, which converges on
+// graphs such as bipartite ones where plain power iteration oscillates. If the
+// iteration does not converge, a *ConvergenceError is returned
+func EigenvectorCentrality(g *structures.Graph) (map[int]float64, error) {
+	from, edges := inEdges(g)
+	x := make([]float64, len(g.Nodes))
+	for i := range x {
+		x[i] = 1 / float64(len(x))
+	}
+
+	x, err := iterateScores(x, func(x []float64) []float64 {
+		y := make([]float64, len(x))
+		length := 0.0
+		for i := range y {
+			y[i] = x[i]
+			for k, j := range from[i] {
+				y[i] += edges[i][k].Weight * x[j]
+			}
+			length += y[i] * y[i]
+		}
+		if length > 0 {
+			for i := range y {
+				y[i] /= math.Sqrt(length)
+			}
+		}
+		return y
+	})
+	if err != nil {
+		return nil, fmt.Errorf("eigenvector centrality: %w", err)
+	}
+	return scoreMap(g, x, true), nil
+}
+
+// KatzCentrality scores each node of g by the number of walks that end at it,
+// where a walk of k edges counts alpha^k times the product of its edge
+// weights, and every node starts with score beta. The scores are scaled to
+// unit length. alpha must be less than the inverse of the largest eigenvalue
+// of the weighted adjacency matrix, otherwise the scores diverge and a
+// *ConvergenceError is returned
+func KatzCentrality(g *structures.Graph, alpha, beta float64) (map[int]float64, error) {
+	from, edges := inEdges(g)
+	x, err := iterateScores(make([]float64, len(g.Nodes)), func(x []float64) []float64 {
+		y := make([]float64, len(x))
+		for i := range y {
+			for k, j := range from[i] {
+				y[i] += edges[i][k].Weight * x[j]
+			}
+			y[i] = alpha*y[i] + beta
+		}
+		return y
+	})
+	if err != nil {
+		return nil, fmt.Errorf("katz centrality: %w", err)
+	}
+	return scoreMap(g, x, true), nil
+}
+
+// PageRank scores each node of g by the long run fraction of time a random
+// surfer spends at it. With probability damping the surfer follows an edge
+// from its node, chosen with probability proportional to edge weight, and
+// otherwise jumps to a node chosen uniformly. Surfers at nodes without
+// outgoing weight always jump. Scores sum to 1. Weights must not be negative,
+// otherwise a *NegativeWeightError is returned, and if the iteration does not
+// converge, a *ConvergenceError is returned
+func PageRank(g *structures.Graph, damping float64) (map[int]float64, error) {
+	if err := checkWeights(g); err != nil {
+		return nil, fmt.Errorf("pagerank: %w", err)
+	}
+
+	n := float64(len(g.Nodes))
+	out := make([]float64, len(g.Nodes))
+	for i, node := range g.Nodes {
+		for _, e := range node.Edges {
+			out[i] += e.Weight
+		}
+	}
+	from, edges := inEdges(g)
+	x := make([]float64, len(g.Nodes))
+	for i := range x {
+		x[i] = 1 / n
+	}
+
+	x, err := iterateScores(x, func(x []float64) []float64 {
+		dangling := 0.0
+		for i := range x {
+			if out[i] == 0 {
+				dangling += x[i]
+			}
+		}
+		y := make([]float64, len(x))
+		for i := range y {
+			for k, j := range from[i] {
+				y[i] += x[j] * edges[i][k].Weight / out[j]
+			}
+			y[i] = damping*(y[i]+dangling/n) + (1-damping)/n
+		}
+		return y
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: %w", err)
+	}
+	return scoreMap(g, x, false), nil
+}
+
+// ShowScores displays the scores of the nodes of g, keyed by node ID, by
+// coloring and sizing each node. Colors range from yellow for the lowest score
+// to red for the highest, and sizes range from minScoreSize to maxScoreSize.
+// Nodes without a score are left as they are
+func ShowScores(g *structures.Graph, scores map[int]float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, s := range scores {
+		low = math.Min(low, s)
+		high = math.Max(high, s)
+	}
+
+	for _, n := range g.Nodes {
+		s, ok := scores[n.ID]
+		if !ok {
+			continue
+		}
+		t := 0.0
+		if high > low {
+			t = (s - low) / (high - low)
+		}
+
+		data, ok := structures.ColorDataFromData(n.Extra)
+		if !ok {
+			data = structures.ColorData{Type: structures.DataNodeTag}
+		}
+		data.Color = blendColors(structures.Colors["yellow"], structures.Colors["red"], t)
+		data.Size = minScoreSize + t*(maxScoreSize-minScoreSize)
+		n.Extra = data
+	}
+}
+
+// ResetSizes returns every node of g to the default display size
+func ResetSizes(g *structures.Graph) {
+	for _, n := range g.Nodes {
+		if data, ok := structures.ColorDataFromData(n.Extra); ok && data.Size != 0 {
+			data.Size = 0
+			n.Extra = data
+		}
+	}
+}
+
+// blendColors returns the color a fraction t of the way from color a to color
+// b, where both are given as "#rrggbb"
+func blendColors(a, b string, t float64) string {
+	blended := "#"
+	for i := 1; i < 7; i += 2 {
+		ca, _ := strconv.ParseUint(a[i:i+2], 16, 8)
+		cb, _ := strconv.ParseUint(b[i:i+2], 16, 8)
+		c := math.Round(float64(ca) + t*(float64(cb)-float64(ca)))
+		blended += fmt.Sprintf("%02x", int(c))
+	}
+	return blended
+}
@@ -0,0 +1,161 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestCentrality(t *testing.T) {
+	log.Printf("Testing centrality")
+
+	// A star with center 0 and leaves 1 through 4
+	star := func(t *testing.T) *structures.Graph {
+		return mockUndirectedGraph(t, 5, []mockEdge{{0, 1, 1}, {0, 2, 1}, {0, 3, 1}, {0, 4, 1}})
+	}
+
+	t.Run("Distances", func(t *testing.T) {
+		g := star(t)
+		checkScores(t, "Degree", DegreeCentrality(g), map[int]float64{0: 1, 1: 0.25})
+		checkScores(t, "In-degree", InDegreeCentrality(g), map[int]float64{0: 1, 1: 0.25})
+		checkScores(t, "Out-degree", OutDegreeCentrality(g), map[int]float64{0: 1, 1: 0.25})
+
+		closeness, err := ClosenessCentrality(g, false)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find closeness centrality: %v", err))
+		}
+		checkScores(t, "Closeness", closeness, map[int]float64{0: 1, 1: 4.0 / 7})
+		harmonic, err := HarmonicCentrality(g, false)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find harmonic centrality: %v", err))
+		}
+		checkScores(t, "Harmonic", harmonic, map[int]float64{0: 1, 1: 0.625})
+
+		// Node 2 cannot reach node 0 but reaches node 1
+		g = mockGraph(t, 3, []mockEdge{{0, 1, 1}, {2, 1, 1}, {0, 2, 1}})
+		closeness, _ = ClosenessCentrality(g, false)
+		checkScores(t, "Directed closeness", closeness, map[int]float64{0: 1, 1: 0, 2: 0.5})
+
+		g = mockGraph(t, 2, []mockEdge{{0, 1, -1}})
+		var negative *NegativeWeightError
+		if _, err = ClosenessCentrality(g, true); !errors.As(err, &negative) {
+			t.Fatalf("Weighted closeness with a negative weight should fail with NegativeWeightError")
+		}
+		if _, err = ClosenessCentrality(g, false); err != nil {
+			t.Fatalf(fmt.Sprintf("Unweighted closeness should ignore weights: %v", err))
+		}
+	})
+
+	t.Run("Betweenness", func(t *testing.T) {
+		b, err := BetweennessCentrality(star(t), false)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find betweenness centrality: %v", err))
+		}
+		checkScores(t, "Star betweenness", b, map[int]float64{0: 1, 1: 0})
+
+		// Shortest paths between 0 and 2 are split between 1 and 3
+		g := mockUndirectedGraph(t, 4, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}})
+		b, _ = BetweennessCentrality(g, false)
+		checkScores(t, "Square betweenness", b, map[int]float64{0: 1.0 / 6, 1: 1.0 / 6})
+
+		// The lightest path from 0 to 2 passes through 1, but the shortest
+		// does not
+		g = mockUndirectedGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}, {0, 2, 3}})
+		b, _ = BetweennessCentrality(g, false)
+		checkScores(t, "Unweighted betweenness", b, map[int]float64{1: 0})
+		b, err = BetweennessCentrality(g, true)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find weighted betweenness centrality: %v", err))
+		}
+		checkScores(t, "Weighted betweenness", b, map[int]float64{0: 0, 1: 1})
+	})
+
+	t.Run("Spectral", func(t *testing.T) {
+		g := star(t)
+		e, err := EigenvectorCentrality(g)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find eigenvector centrality: %v", err))
+		}
+		checkScores(t, "Eigenvector", e, map[int]float64{0: 2 / math.Sqrt(8), 1: 1 / math.Sqrt(8)})
+
+		k, err := KatzCentrality(g, 0.1, 1)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find Katz centrality: %v", err))
+		}
+		center, leaf := 1.4/0.96, 1+0.14/0.96
+		length := math.Sqrt(center*center + 4*leaf*leaf)
+		checkScores(t, "Katz", k, map[int]float64{0: center / length, 1: leaf / length})
+
+		var convergence *ConvergenceError
+		if _, err = KatzCentrality(g, 1, 1); !errors.As(err, &convergence) {
+			t.Fatalf("Katz centrality with too large an alpha should fail with ConvergenceError")
+		}
+	})
+
+	t.Run("PageRank", func(t *testing.T) {
+		g := mockUndirectedGraph(t, 4, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}})
+		p, err := PageRank(g, 0.85)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find PageRank: %v", err))
+		}
+		checkScores(t, "Cycle PageRank", p, map[int]float64{0: 0.25, 1: 0.25, 2: 0.25, 3: 0.25})
+
+		// Node 1 has no outgoing edges, so surfers there jump anywhere
+		g = mockGraph(t, 2, []mockEdge{{0, 1, 1}})
+		p, _ = PageRank(g, 0.85)
+		checkScores(t, "Dangling PageRank", p, map[int]float64{0: 0.5 / 1.425, 1: 1 - 0.5/1.425})
+
+		// Edges are followed in proportion to their weight
+		g = mockGraph(t, 3, []mockEdge{{0, 1, 3}, {0, 2, 1}, {1, 0, 1}, {2, 0, 1}})
+		p, err = PageRank(g, 0.5)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find PageRank: %v", err))
+		}
+		checkScores(t, "Weighted PageRank", p, map[int]float64{0: 4.0 / 9, 1: 1.0 / 3, 2: 2.0 / 9})
+
+		g = mockGraph(t, 2, []mockEdge{{0, 1, -1}})
+		var negative *NegativeWeightError
+		if _, err = PageRank(g, 0.85); !errors.As(err, &negative) {
+			t.Fatalf("PageRank with a negative weight should fail with NegativeWeightError")
+		}
+	})
+
+	t.Run("Display", func(t *testing.T) {
+		g := star(t)
+		ShowScores(g, map[int]float64{0: 2, 1: 1, 2: 0})
+		for id, want := range map[int]structures.ColorData{
+			0: {Color: structures.Colors["red"], Size: maxScoreSize},
+			1: {Color: blendColors(structures.Colors["yellow"], structures.Colors["red"], 0.5), Size: 2},
+			2: {Color: structures.Colors["yellow"], Size: minScoreSize},
+			3: {Color: structures.Colors["orange"]},
+		} {
+			n, _ := g.GetNodeByID(id)
+			data, _ := structures.ColorDataFromData(n.Extra)
+			if data.Color != want.Color || data.Size != want.Size {
+				t.Fatalf(fmt.Sprintf("Node %d should be shown as %v but is %v", id, want, data))
+			}
+		}
+
+		ResetSizes(g)
+		for _, n := range g.Nodes {
+			data, _ := structures.ColorDataFromData(n.Extra)
+			if data.Size != 0 {
+				t.Fatalf(fmt.Sprintf("Node %d should be reset to the default size but has size %f", n.ID, data.Size))
+			}
+		}
+	})
+}
+
+// checkScores checks that the scores include the wanted scores
+func checkScores(t *testing.T, name string, scores, want map[int]float64) {
+	t.Helper()
+	for id, w := range want {
+		if s, ok := scores[id]; !ok || math.Abs(s-w) > 1e-6 {
+			t.Fatalf(fmt.Sprintf("%s of node %d should be %f but is %f", name, id, w, s))
+		}
+	}
+}
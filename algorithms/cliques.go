@@ -0,0 +1,328 @@
+package algorithms
+
+import (
+	"sort"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// adjacencyMatrix returns whether each pair of node positions of g viewed as
+// undirected is joined by an edge, along with the neighbors of each position
+// as returned by adjacency. If complement is set, pairs of distinct nodes are
+// joined exactly when g does not join them
+func adjacencyMatrix(g *structures.Graph, complement bool) ([][]bool, [][]int) {
+	adj := adjacency(g)
+	n := len(adj)
+	joined := make([][]bool, n)
+	for i := range joined {
+		joined[i] = make([]bool, n)
+		for _, j := range adj[i] {
+			joined[i][j] = true
+		}
+	}
+	if !complement {
+		return joined, adj
+	}
+
+	for i := range joined {
+		adj[i] = adj[i][:0]
+		for j := range joined[i] {
+			joined[i][j] = i != j && !joined[i][j]
+			if joined[i][j] {
+				adj[i] = append(adj[i], j)
+			}
+		}
+	}
+	return joined, adj
+}
+
+// nodeSet returns the IDs of the nodes of g at the positions, in increasing
+// order
+func nodeSet(g *structures.Graph, positions []int) []int {
+	ids := make([]int, len(positions))
+	for k, i := range positions {
+		ids[k] = g.Nodes[i].ID
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// MaximalCliques lists every maximal clique of g viewed as undirected, a set of
+// nodes that are all joined to each other and that no other node is joined to
+// all of, with the algorithm of Bron and Kerbosch. The outer level takes the
+// nodes in degeneracy order, so that each search only considers neighbors of
+// a node that come later, and each level below leaves out the neighbors of a
+// pivot node, whose cliques are found through the pivot. Each clique holds its
+// node IDs in increasing order. Self loops are ignored, and a node without
+// neighbors is a clique of its own
+func MaximalCliques(g *structures.Graph) [][]int {
+	joined, adj := adjacencyMatrix(g, false)
+	var cliques [][]int
+
+	// extend reports the maximal cliques that hold the nodes of r, some of the
+	// candidates p and none of the excluded nodes x
+	var extend func(r, p, x []int)
+	extend = func(r, p, x []int) {
+		if len(p) == 0 {
+			if len(x) == 0 {
+				cliques = append(cliques, nodeSet(g, r))
+			}
+			return
+		}
+
+		// The pivot has the most neighbors among the candidates
+		pivot, most := -1, -1
+		for _, candidates := range [][]int{p, x} {
+			for _, u := range candidates {
+				count := 0
+				for _, w := range p {
+					if joined[u][w] {
+						count++
+					}
+				}
+				if count > most {
+					pivot, most = u, count
+				}
+			}
+		}
+
+		for _, w := range append([]int{}, p...) {
+			if joined[pivot][w] {
+				continue
+			}
+			extend(append(r[:len(r):len(r)], w), neighborsIn(joined, w, p), neighborsIn(joined, w, x))
+			p = removeInt(p, w)
+			x = append(x, w)
+		}
+	}
+
+	order := degeneracyOrder(adj)
+	position := make([]int, len(order))
+	for k, i := range order {
+		position[i] = k
+	}
+	for _, i := range order {
+		var p, x []int
+		for _, j := range adj[i] {
+			if position[j] > position[i] {
+				p = append(p, j)
+			} else {
+				x = append(x, j)
+			}
+		}
+		extend([]int{i}, p, x)
+	}
+	return cliques
+}
+
+// neighborsIn returns the nodes of s joined to node i
+func neighborsIn(joined [][]bool, i int, s []int) []int {
+	var in []int
+	for _, j := range s {
+		if joined[i][j] {
+			in = append(in, j)
+		}
+	}
+	return in
+}
+
+// removeInt returns s without the first element equal to x, reusing s
+func removeInt(s []int, x int) []int {
+	if i := indexOf(s, x); i != -1 {
+		return append(s[:i], s[i+1:]...)
+	}
+	return s
+}
+
+// maximumClique returns the node positions of a largest clique of the graph
+// given by joined and adj with the branch and bound algorithm of Tomita and
+// Seki. Candidates are greedily colored, and since a clique takes at most one
+// node of each color, branches with too few colors left to beat the best
+// clique are pruned
+func maximumClique(joined [][]bool, adj [][]int) []int {
+	var best []int
+
+	// colorSort orders the candidates p by greedy color and returns the color
+	// of each, counting from 1
+	colorSort := func(p []int) ([]int, []int) {
+		var classes [][]int
+		for _, v := range p {
+			c := 0
+			for ; c < len(classes); c++ {
+				if len(neighborsIn(joined, v, classes[c])) == 0 {
+					break
+				}
+			}
+			if c == len(classes) {
+				classes = append(classes, nil)
+			}
+			classes[c] = append(classes[c], v)
+		}
+		order := make([]int, 0, len(p))
+		colors := make([]int, 0, len(p))
+		for c, class := range classes {
+			for _, v := range class {
+				order = append(order, v)
+				colors = append(colors, c+1)
+			}
+		}
+		return order, colors
+	}
+
+	var expand func(c, p []int)
+	expand = func(c, p []int) {
+		order, colors := colorSort(p)
+		for i := len(order) - 1; i >= 0; i-- {
+			if len(c)+colors[i] <= len(best) {
+				return
+			}
+			next := append(c[:len(c):len(c)], order[i])
+			if candidates := neighborsIn(joined, order[i], order[:i]); len(candidates) > 0 {
+				expand(next, candidates)
+			} else if len(next) > len(best) {
+				best = next
+			}
+		}
+	}
+
+	// Starting from the nodes of greatest degree finds large cliques early
+	p := make([]int, len(adj))
+	for i := range p {
+		p[i] = i
+	}
+	sort.SliceStable(p, func(a, b int) bool {
+		return len(adj[p[a]]) > len(adj[p[b]])
+	})
+	expand(nil, p)
+	return best
+}
+
+// MaximumClique finds a largest clique of g viewed as undirected, a set of
+// nodes that are all joined to each other, with the branch and bound algorithm
+// of Tomita and Seki. The search takes exponential time, so it suits small
+// graphs. The clique holds its node IDs in increasing order. Self loops are
+// ignored
+func MaximumClique(g *structures.Graph) []int {
+	return nodeSet(g, maximumClique(adjacencyMatrix(g, false)))
+}
+
+// MaximumIndependentSet finds a largest independent set of g viewed as
+// undirected, a set of nodes no two of which are joined, as a largest clique of
+// the complement of g. The search takes exponential time, so it suits small
+// graphs. The set holds its node IDs in increasing order. Self loops are
+// ignored
+func MaximumIndependentSet(g *structures.Graph) []int {
+	return nodeSet(g, maximumClique(adjacencyMatrix(g, true)))
+}
+
+// MinimumVertexCover finds a smallest vertex cover of g viewed as undirected, a
+// set of nodes that holds an end of every edge, as the nodes left out of a
+// largest independent set. The search takes exponential time, so it suits
+// small graphs. The cover holds its node IDs in increasing order. Self loops
+// are ignored
+func MinimumVertexCover(g *structures.Graph) []int {
+	return complementSet(g, MaximumIndependentSet(g))
+}
+
+// complementSet returns the IDs of the nodes of g not in the set of IDs s, in
+// increasing order
+func complementSet(g *structures.Graph, s []int) []int {
+	in := make(map[int]bool, len(s))
+	for _, id := range s {
+		in[id] = true
+	}
+	var rest []int
+	for _, n := range g.Nodes {
+		if !in[n.ID] {
+			rest = append(rest, n.ID)
+		}
+	}
+	sort.Ints(rest)
+	return rest
+}
+
+// GreedyClique finds a large clique of g viewed as undirected without
+// searching. From each node in turn it repeatedly adds the candidate joined to
+// the most other candidates, where the candidates are the nodes joined to every
+// node of the clique so far, and keeps the largest clique found. The clique
+// holds its node IDs in increasing order. Self loops are ignored
+func GreedyClique(g *structures.Graph) []int {
+	joined, adj := adjacencyMatrix(g, false)
+	var best []int
+	for i := range adj {
+		clique := []int{i}
+		candidates := append([]int{}, adj[i]...)
+		for len(candidates) > 0 {
+			next, most := -1, -1
+			for _, v := range candidates {
+				if count := len(neighborsIn(joined, v, candidates)); count > most {
+					next, most = v, count
+				}
+			}
+			clique = append(clique, next)
+			candidates = neighborsIn(joined, next, candidates)
+		}
+		if len(clique) > len(best) {
+			best = clique
+		}
+	}
+	return nodeSet(g, best)
+}
+
+// GreedyIndependentSet finds a large independent set of g viewed as
+// undirected without searching. It repeatedly adds a node of least degree
+// among the nodes left and removes it and its neighbors, which finds a set at
+// least n/(d+1) nodes large for average degree d. The set holds its node IDs in
+// increasing order. Self loops are ignored
+func GreedyIndependentSet(g *structures.Graph) []int {
+	adj := adjacency(g)
+	degree := make([]int, len(adj))
+	for i := range adj {
+		degree[i] = len(adj[i])
+	}
+
+	removed := make([]bool, len(adj))
+	remove := func(i int) {
+		removed[i] = true
+		for _, j := range adj[i] {
+			degree[j]--
+		}
+	}
+	var set []int
+	for {
+		next := -1
+		for i := range adj {
+			if !removed[i] && (next == -1 || degree[i] < degree[next]) {
+				next = i
+			}
+		}
+		if next == -1 {
+			break
+		}
+		set = append(set, next)
+		remove(next)
+		for _, j := range adj[next] {
+			if !removed[j] {
+				remove(j)
+			}
+		}
+	}
+	return nodeSet(g, set)
+}
+
+// ApproximateVertexCover finds a vertex cover of g viewed as undirected at
+// most twice as large as the smallest by taking both ends of each edge of a
+// maximal matching, built by adding edges in order while neither end is
+// covered. The cover holds its node IDs in increasing order. Self loops are
+// ignored
+func ApproximateVertexCover(g *structures.Graph) []int {
+	covered := make([]bool, len(g.Nodes))
+	var cover []int
+	for _, ue := range undirectedEdges(g) {
+		if !covered[ue.i] && !covered[ue.j] {
+			covered[ue.i], covered[ue.j] = true, true
+			cover = append(cover, ue.i, ue.j)
+		}
+	}
+	return nodeSet(g, cover)
+}
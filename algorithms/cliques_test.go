@@ -0,0 +1,192 @@
+package algorithms
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestCliques(t *testing.T) {
+	log.Printf("Testing cliques")
+
+	t.Run("Maximal", func(t *testing.T) {
+		// Triangles 0 1 2 and 1 2 3 share an edge, with a path 3 4 and
+		// isolated node 5
+		edges := []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {1, 3, 1}, {2, 3, 1}, {3, 4, 1}}
+		g := mockUndirectedGraph(t, 6, edges)
+		checkCliques(t, MaximalCliques(g), [][]int{{0, 1, 2}, {1, 2, 3}, {3, 4}, {5}})
+
+		// Edge direction and self loops are ignored
+		g = mockGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {1, 1, 1}})
+		checkCliques(t, MaximalCliques(g), [][]int{{0, 1, 2}})
+
+		if cliques := MaximalCliques(mockGraph(t, 0, nil)); len(cliques) != 0 {
+			t.Fatalf(fmt.Sprintf("Empty graph should have no cliques but has %v", cliques))
+		}
+	})
+
+	t.Run("Exact", func(t *testing.T) {
+		// A 5-cycle 0 to 4 with node 5 joined to 0 and 1
+		g := mockUndirectedGraph(t, 6, []mockEdge{
+			{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 0, 1}, {5, 0, 1}, {5, 1, 1},
+		})
+		checkInts(t, "Maximum clique", MaximumClique(g), []int{0, 1, 5})
+		checkInts(t, "Maximum independent set", MaximumIndependentSet(g), []int{2, 4, 5})
+		checkInts(t, "Minimum vertex cover", MinimumVertexCover(g), []int{0, 1, 3})
+
+		g = mockGraph(t, 0, nil)
+		if len(MaximumClique(g)) != 0 || len(MaximumIndependentSet(g)) != 0 || len(MinimumVertexCover(g)) != 0 {
+			t.Fatalf("Empty graph should have empty sets")
+		}
+	})
+
+	t.Run("Random graphs", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 100; i++ {
+			n := 1 + r.Intn(9)
+			var edges []mockEdge
+			for a := 0; a < n; a++ {
+				for b := a + 1; b < n; b++ {
+					if r.Intn(2) == 0 {
+						edges = append(edges, mockEdge{a, b, 1})
+					}
+				}
+			}
+			g := mockUndirectedGraph(t, n, edges)
+			joined := make([][]bool, n)
+			for a := range joined {
+				joined[a] = make([]bool, n)
+			}
+			for _, e := range edges {
+				joined[e.from][e.to], joined[e.to][e.from] = true, true
+			}
+
+			maximal, largest, independent := bruteForceCliques(joined)
+			checkCliques(t, MaximalCliques(g), maximal)
+			checkSet(t, joined, MaximumClique(g), true, largest)
+			checkSet(t, joined, MaximumIndependentSet(g), false, independent)
+			cover := MinimumVertexCover(g)
+			checkCover(t, edges, cover, n-independent)
+
+			checkSet(t, joined, GreedyClique(g), true, 0)
+			checkSet(t, joined, GreedyIndependentSet(g), false, 0)
+			checkCover(t, edges, ApproximateVertexCover(g), 0)
+			if approx := ApproximateVertexCover(g); len(approx) > 2*len(cover) {
+				t.Fatalf(fmt.Sprintf("Vertex cover %v should be at most twice as large as %v", approx, cover))
+			}
+		}
+	})
+}
+
+// checkCliques checks that the cliques are the wanted cliques in any order
+func checkCliques(t *testing.T, got, want [][]int) {
+	t.Helper()
+	key := func(cliques [][]int) []string {
+		keys := make([]string, len(cliques))
+		for i, c := range cliques {
+			keys[i] = fmt.Sprint(c)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	if fmt.Sprint(key(got)) != fmt.Sprint(key(want)) {
+		t.Fatalf(fmt.Sprintf("Cliques should be %v but are %v", want, got))
+	}
+}
+
+// checkSet checks that a set of node IDs in increasing order is a clique if
+// clique is set, or an independent set otherwise, of the wanted size. A size
+// of 0 only checks that the set cannot be extended
+func checkSet(t *testing.T, joined [][]bool, set []int, clique bool, size int) {
+	t.Helper()
+	if !sort.IntsAreSorted(set) || (size > 0 && len(set) != size) {
+		t.Fatalf(fmt.Sprintf("Set %v should have %d nodes in increasing order", set, size))
+	}
+	in := make(map[int]bool)
+	for i, a := range set {
+		in[a] = true
+		for _, b := range set[i+1:] {
+			if joined[a][b] != clique {
+				t.Fatalf(fmt.Sprintf("Nodes %d and %d of set %v should be joined: %t", a, b, set, clique))
+			}
+		}
+	}
+	for a := range joined {
+		extends := !in[a]
+		for _, b := range set {
+			if extends && joined[a][b] != clique {
+				extends = false
+			}
+		}
+		if extends {
+			t.Fatalf(fmt.Sprintf("Set %v could be extended by node %d", set, a))
+		}
+	}
+}
+
+// checkCover checks that a set of node IDs holds an end of each edge and, unless
+// size is 0, has the wanted size
+func checkCover(t *testing.T, edges []mockEdge, cover []int, size int) {
+	t.Helper()
+	in := make(map[int]bool)
+	for _, id := range cover {
+		in[id] = true
+	}
+	for _, e := range edges {
+		if !in[e.from] && !in[e.to] {
+			t.Fatalf(fmt.Sprintf("Vertex cover %v should cover edge %v", cover, e))
+		}
+	}
+	if size > 0 && len(cover) != size {
+		t.Fatalf(fmt.Sprintf("Vertex cover %v should have %d nodes", cover, size))
+	}
+}
+
+// bruteForceCliques returns the maximal cliques of the graph given by joined,
+// along with the sizes of its largest clique and independent set, by checking
+// every subset of nodes
+func bruteForceCliques(joined [][]bool) ([][]int, int, int) {
+	n := len(joined)
+	var maximal [][]int
+	largest, independent := 0, 0
+	for mask := 1; mask < 1<<n; mask++ {
+		var set []int
+		for a := 0; a < n; a++ {
+			if mask&(1<<a) != 0 {
+				set = append(set, a)
+			}
+		}
+		clique, stable := true, true
+		for i, a := range set {
+			for _, b := range set[i+1:] {
+				clique = clique && joined[a][b]
+				stable = stable && !joined[a][b]
+			}
+		}
+		if stable && len(set) > independent {
+			independent = len(set)
+		}
+		if !clique {
+			continue
+		}
+		if len(set) > largest {
+			largest = len(set)
+		}
+		extends := false
+		for a := 0; a < n && !extends; a++ {
+			if mask&(1<<a) != 0 {
+				continue
+			}
+			extends = true
+			for _, b := range set {
+				extends = extends && joined[a][b]
+			}
+		}
+		if !extends {
+			maximal = append(maximal, set)
+		}
+	}
+	return maximal, largest, independent
+}
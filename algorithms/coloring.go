@@ -0,0 +1,530 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+const (
+	// goldenRatioConjugate is the fraction of a turn by which the hue of each
+	// generated color steps past the last, so that nearby colors differ
+	goldenRatioConjugate = 0.618033988749895
+)
+
+// Coloring assigns colors to the nodes of a graph so that no two adjacent nodes
+// share a color
+type Coloring struct {
+	// Colors maps each node ID to its color, numbered from 0
+	Colors map[int]int
+	// NumColors is the number of colors used
+	NumColors int
+}
+
+// EdgeColoring assigns colors to the edges of a graph viewed as undirected so
+// that no two edges at a node share a color
+type EdgeColoring struct {
+	// Colors maps the undirected key of each edge to its color, numbered from 0
+	Colors map[EdgeKey]int
+	// NumColors is the number of colors used
+	NumColors int
+}
+
+// adjacency returns the neighbors of each node of g viewed as undirected, by
+// position in g and in increasing order. Self loops are left out
+func adjacency(g *structures.Graph) [][]int {
+	adj := make([][]int, len(g.Nodes))
+	for _, ue := range undirectedEdges(g) {
+		adj[ue.i] = append(adj[ue.i], ue.j)
+		adj[ue.j] = append(adj[ue.j], ue.i)
+	}
+	for _, a := range adj {
+		sort.Ints(a)
+	}
+	return adj
+}
+
+// newColoring collects the coloring of g from the color of each node position
+func newColoring(g *structures.Graph, color []int) *Coloring {
+	c := &Coloring{Colors: make(map[int]int, len(color))}
+	for i, n := range g.Nodes {
+		c.Colors[n.ID] = color[i]
+		if color[i] >= c.NumColors {
+			c.NumColors = color[i] + 1
+		}
+	}
+	return c
+}
+
+// smallestFreeColor returns the smallest color not taken by a neighbor of
+// node position i, where uncolored nodes have color -1
+func smallestFreeColor(adj [][]int, color []int, i int) int {
+	taken := make(map[int]bool, len(adj[i]))
+	for _, j := range adj[i] {
+		taken[color[j]] = true
+	}
+	c := 0
+	for taken[c] {
+		c++
+	}
+	return c
+}
+
+// GreedyColoring colors the nodes of g viewed as undirected in the order given
+// by node IDs, giving each node the smallest color not taken by its neighbors.
+// Nodes left out of the order are colored after it, in order of position in
+// g. Self loops are ignored
+func GreedyColoring(g *structures.Graph, order []int) *Coloring {
+	index := make(map[int]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n.ID] = i
+	}
+	positions := make([]int, 0, len(g.Nodes))
+	for _, id := range order {
+		if i, ok := index[id]; ok {
+			positions = append(positions, i)
+		}
+	}
+	for i := range g.Nodes {
+		positions = append(positions, i)
+	}
+
+	adj := adjacency(g)
+	color := make([]int, len(g.Nodes))
+	for i := range color {
+		color[i] = -1
+	}
+	for _, i := range positions {
+		if color[i] == -1 {
+			color[i] = smallestFreeColor(adj, color, i)
+		}
+	}
+	return newColoring(g, color)
+}
+
+// LargestFirstOrder orders the node IDs of g viewed as undirected by
+// decreasing degree, with ties in order of position in g
+func LargestFirstOrder(g *structures.Graph) []int {
+	adj := adjacency(g)
+	positions := make([]int, len(g.Nodes))
+	for i := range positions {
+		positions[i] = i
+	}
+	sort.SliceStable(positions, func(a, b int) bool {
+		return len(adj[positions[a]]) > len(adj[positions[b]])
+	})
+
+	order := make([]int, len(positions))
+	for k, i := range positions {
+		order[k] = g.Nodes[i].ID
+	}
+	return order
+}
+
+// SmallestLastOrder orders the node IDs of g viewed as undirected by
+// repeatedly removing a node of smallest degree among the nodes left, and
+// listing the nodes in reverse order of removal. Greedy coloring in this order
+// uses at most one more color than the degeneracy of g
+func SmallestLastOrder(g *structures.Graph) []int {
+	removal := degeneracyOrder(adjacency(g))
+	order := make([]int, len(removal))
+	for k, i := range removal {
+		order[len(order)-1-k] = g.Nodes[i].ID
+	}
+	return order
+}
+
+// degeneracyOrder returns the node positions of adj in the order that they
+// are removed by repeatedly removing a node of smallest degree among the nodes
+// left, with ties going to the lowest position. Each node has at most as many
+// neighbors later in the order as the degeneracy of the graph
+func degeneracyOrder(adj [][]int) []int {
+	// buckets holds the nodes left by degree, each queued by position. Entries
+	// are not removed when a degree drops, so stale entries are skipped
+	degree := make([]int, len(adj))
+	buckets := make([]priorityQueue, len(adj)+1)
+	for i := range adj {
+		degree[i] = len(adj[i])
+		buckets[degree[i]].push(i, float64(i))
+	}
+
+	removed := make([]bool, len(adj))
+	order := make([]int, 0, len(adj))
+	for low := 0; len(order) < len(adj); {
+		if buckets[low].Len() == 0 {
+			low++
+			continue
+		}
+		next := buckets[low].pop().id
+		if removed[next] || degree[next] != low {
+			continue
+		}
+		removed[next] = true
+		for _, j := range adj[next] {
+			if !removed[j] {
+				degree[j]--
+				buckets[degree[j]].push(j, float64(j))
+				if degree[j] < low {
+					low = degree[j]
+				}
+			}
+		}
+		order = append(order, next)
+	}
+	return order
+}
+
+// dsatur colors the nodes of adj with the DSatur heuristic and returns the
+// color of each node position
+func dsatur(adj [][]int) []int {
+	n := len(adj)
+	color := make([]int, n)
+	for i := range color {
+		color[i] = -1
+	}
+
+	// buckets holds the uncolored nodes by saturation, the number of distinct
+	// colors among their neighbors, each queued by greatest degree and then
+	// lowest position. Entries are not removed when a saturation rises, so
+	// stale entries are skipped
+	saturation := make([]int, n)
+	neighborColors := make([]map[int]bool, n)
+	buckets := make([]priorityQueue, n+1)
+	priority := func(i int) float64 {
+		return float64(i - len(adj[i])*n)
+	}
+	for i := range adj {
+		neighborColors[i] = make(map[int]bool)
+		buckets[0].push(i, priority(i))
+	}
+
+	for high, colored := 0, 0; colored < n; {
+		if buckets[high].Len() == 0 {
+			high--
+			continue
+		}
+		next := buckets[high].pop().id
+		if color[next] != -1 || saturation[next] != high {
+			continue
+		}
+		c := smallestFreeColor(adj, color, next)
+		color[next] = c
+		colored++
+		for _, j := range adj[next] {
+			if color[j] == -1 && !neighborColors[j][c] {
+				neighborColors[j][c] = true
+				saturation[j]++
+				buckets[saturation[j]].push(j, priority(j))
+				if saturation[j] > high {
+					high = saturation[j]
+				}
+			}
+		}
+	}
+	return color
+}
+
+// mostSaturated returns the uncolored node position of adj with the most
+// distinct colors among its neighbors, breaking ties by greatest degree and
+// then by lowest position, or -1 if every node is colored. It scans every
+// node, which suits the backtracking of ChromaticColoring where colors are
+// also taken back
+func mostSaturated(adj [][]int, color []int) int {
+	next, nextSaturation := -1, -1
+	for i := range adj {
+		if color[i] != -1 {
+			continue
+		}
+		seen := make(map[int]bool)
+		for _, j := range adj[i] {
+			if color[j] != -1 {
+				seen[color[j]] = true
+			}
+		}
+		if s := len(seen); s > nextSaturation || s == nextSaturation && len(adj[i]) > len(adj[next]) {
+			next, nextSaturation = i, s
+		}
+	}
+	return next
+}
+
+// DSaturColoring colors the nodes of g viewed as undirected with Brélaz's
+// DSatur heuristic, which next colors the node whose neighbors already have
+// the most distinct colors, giving it the smallest color not taken by them.
+// Ties go to the node of greatest degree. Self loops are ignored
+func DSaturColoring(g *structures.Graph) *Coloring {
+	return newColoring(g, dsatur(adjacency(g)))
+}
+
+// ChromaticColoring colors the nodes of g viewed as undirected with the fewest
+// colors possible, the chromatic number of g. It searches colorings by branch
+// and bound, coloring the most saturated node next as in DSatur and pruning
+// colorings that cannot use fewer colors than the best found. The search takes
+// exponential time, so it suits small graphs. Self loops are ignored
+func ChromaticColoring(g *structures.Graph) *Coloring {
+	adj := adjacency(g)
+	best := dsatur(adj)
+	bestColors := 0
+	for _, c := range best {
+		if c+1 > bestColors {
+			bestColors = c + 1
+		}
+	}
+
+	// A clique needs a color for each of its nodes, so the search stops once
+	// it finds a coloring with as many colors as a greedily found clique
+	lower := 0
+	adjacent := make([]map[int]bool, len(adj))
+	for i := range adj {
+		adjacent[i] = make(map[int]bool, len(adj[i]))
+		for _, j := range adj[i] {
+			adjacent[i][j] = true
+		}
+	}
+	for i := range adj {
+		clique := []int{i}
+		for _, j := range adj[i] {
+			inClique := true
+			for _, k := range clique {
+				if !adjacent[j][k] {
+					inClique = false
+					break
+				}
+			}
+			if inClique {
+				clique = append(clique, j)
+			}
+		}
+		if len(clique) > lower {
+			lower = len(clique)
+		}
+	}
+
+	color := make([]int, len(adj))
+	for i := range color {
+		color[i] = -1
+	}
+	// search colors the rest of the nodes given the number colored and the
+	// number of colors used so far
+	var search func(colored, used int)
+	search = func(colored, used int) {
+		if colored == len(adj) {
+			copy(best, color)
+			bestColors = used
+			return
+		}
+		i := mostSaturated(adj, color)
+		taken := make(map[int]bool, len(adj[i]))
+		for _, j := range adj[i] {
+			taken[color[j]] = true
+		}
+		for c := 0; c <= used && c < bestColors-1 && bestColors > lower; c++ {
+			if taken[c] {
+				continue
+			}
+			color[i] = c
+			if c == used {
+				search(colored+1, used+1)
+			} else {
+				search(colored+1, used)
+			}
+			color[i] = -1
+		}
+	}
+	if bestColors > lower {
+		search(0, 0)
+	}
+	return newColoring(g, best)
+}
+
+// MisraGriesEdgeColoring colors the edges of g viewed as undirected with at
+// most one more color than the greatest degree, as Vizing's theorem allows,
+// using the Misra and Gries algorithm. Each edge is colored by building a
+// maximal fan of edges around one of its nodes, swapping two colors along an
+// alternating path and rotating colors down the fan. Nodes joined in both
+// directions share a single color, and self loops are ignored
+func MisraGriesEdgeColoring(g *structures.Graph) *EdgeColoring {
+	adj := adjacency(g)
+	// at maps each neighbor of each node position to the color of the edge
+	// between them, or -1 while the edge is uncolored
+	at := make([]map[int]int, len(adj))
+	for i := range adj {
+		at[i] = make(map[int]int, len(adj[i]))
+		for _, j := range adj[i] {
+			at[i][j] = -1
+		}
+	}
+	free := func(x, c int) bool {
+		for _, d := range at[x] {
+			if d == c {
+				return false
+			}
+		}
+		return true
+	}
+	freeColor := func(x int) int {
+		c := 0
+		for !free(x, c) {
+			c++
+		}
+		return c
+	}
+	setColor := func(x, y, c int) {
+		at[x][y], at[y][x] = c, c
+	}
+
+	for _, ue := range undirectedEdges(g) {
+		u := ue.i
+		// Build a maximal fan of u starting at the uncolored edge, in which
+		// the color of each edge is free at the node of the edge before it
+		fan := []int{ue.j}
+		inFan := map[int]bool{ue.j: true}
+		for extended := true; extended; {
+			extended = false
+			last := fan[len(fan)-1]
+			for _, w := range adj[u] {
+				if c := at[u][w]; !inFan[w] && c != -1 && free(last, c) {
+					fan = append(fan, w)
+					inFan[w] = true
+					extended = true
+					break
+				}
+			}
+		}
+
+		// Swap colors c and d on the path from u alternating between them,
+		// which leaves d free at u
+		c, d := freeColor(u), freeColor(fan[len(fan)-1])
+		var path [][2]int
+		for x, want := u, d; ; {
+			y := -1
+			for z, col := range at[x] {
+				if col == want {
+					y = z
+					break
+				}
+			}
+			if y == -1 {
+				break
+			}
+			path = append(path, [2]int{x, y})
+			x = y
+			if want == d {
+				want = c
+			} else {
+				want = d
+			}
+		}
+		for _, p := range path {
+			if at[p[0]][p[1]] == c {
+				setColor(p[0], p[1], d)
+			} else {
+				setColor(p[0], p[1], c)
+			}
+		}
+
+		// Rotate colors down the part of the fan that is still a fan up to
+		// the first node where d is free, then color its edge d
+		k := 0
+		for i, w := range fan {
+			if i > 0 && !free(fan[i-1], at[u][w]) {
+				break
+			}
+			if free(w, d) {
+				k = i
+				break
+			}
+		}
+		for i := 0; i < k; i++ {
+			setColor(u, fan[i], at[u][fan[i+1]])
+		}
+		setColor(u, fan[k], d)
+	}
+
+	ec := &EdgeColoring{Colors: make(map[EdgeKey]int)}
+	for i := range at {
+		for j, c := range at[i] {
+			ec.Colors[undirectedKeyOf(g.Nodes[i].ID, g.Nodes[j].ID)] = c
+			if c >= ec.NumColors {
+				ec.NumColors = c + 1
+			}
+		}
+	}
+	return ec
+}
+
+// ShowColoring colors the nodes of g by their color in c, taking colors from
+// the Colors palette in the order of DistinctColors and generating more past
+// them. Colorings of up to 500 colors are drawn without shared colors
+func ShowColoring(g *structures.Graph, c *Coloring) {
+	for _, n := range g.Nodes {
+		if i, ok := c.Colors[n.ID]; ok {
+			showGeneratedColor(n, i)
+		}
+	}
+}
+
+// ShowEdgeColoring colors the edges of g by their color in c, taking colors
+// from the Colors palette in the order of DistinctColors and generating more
+// past them. Colorings of up to 500 colors are drawn without shared colors.
+// Edges joining the same nodes in both directions are colored alike
+func ShowEdgeColoring(g *structures.Graph, c *EdgeColoring) {
+	for _, n := range g.Nodes {
+		for _, e := range n.Edges {
+			if i, ok := c.Colors[undirectedKey(e)]; ok {
+				e.Extra = structures.ColorData{Color: generatedColor(i)}
+			}
+		}
+	}
+}
+
+// generatedColor returns the color numbered i in the order of DistinctColors.
+// Past the palette, the hue steps around the color wheel by the golden ratio,
+// which keeps the first 500 colors different
+func generatedColor(i int) string {
+	if i < len(DistinctColors) {
+		return structures.Colors[DistinctColors[i]]
+	}
+	h := math.Mod(float64(i-len(DistinctColors))*goldenRatioConjugate, 1)
+	return hslColor(h, 0.65, 0.45)
+}
+
+// hslColor returns the hex code of the color with hue h as a fraction of a
+// turn, saturation s and lightness l
+func hslColor(h, s, l float64) string {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(6*h, 2)-1))
+	var r, g, b float64
+	switch int(6 * h) {
+	case 0:
+		r, g = c, x
+	case 1:
+		r, g = x, c
+	case 2:
+		g, b = c, x
+	case 3:
+		g, b = x, c
+	case 4:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := l - c/2
+	channel := func(v float64) int {
+		return int(math.Round((v + m) * 255))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", channel(r), channel(g), channel(b))
+}
+
+// showGeneratedColor sets the color of n to the color numbered i by
+// generatedColor
+func showGeneratedColor(n *structures.Node, i int) {
+	data, ok := structures.ColorDataFromData(n.Extra)
+	if !ok {
+		data = structures.ColorData{Type: structures.DataNodeTag}
+	}
+	data.Color = generatedColor(i)
+	n.Extra = data
+}
@@ -0,0 +1,248 @@
+package algorithms
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestColoring(t *testing.T) {
+	log.Printf("Testing coloring")
+
+	// A crown graph joins each node i < 4 to each node j >= 4 except i+4, so
+	// coloring 0 4 1 5 2 6 3 7 in order uses a color for each pair
+	crown := func(t *testing.T) *structures.Graph {
+		var edges []mockEdge
+		for i := 0; i < 4; i++ {
+			for j := 4; j < 8; j++ {
+				if j != i+4 {
+					edges = append(edges, mockEdge{i, j, 1})
+				}
+			}
+		}
+		return mockUndirectedGraph(t, 8, edges)
+	}
+
+	t.Run("Greedy", func(t *testing.T) {
+		g := crown(t)
+		c := GreedyColoring(g, []int{0, 4, 1, 5, 2, 6, 3, 7})
+		checkColoring(t, g, c, 4)
+
+		algs := map[string]func(*structures.Graph) *Coloring{
+			"LargestFirst": func(g *structures.Graph) *Coloring {
+				return GreedyColoring(g, LargestFirstOrder(g))
+			},
+			"SmallestLast": func(g *structures.Graph) *Coloring {
+				return GreedyColoring(g, SmallestLastOrder(g))
+			},
+			"DSatur":    DSaturColoring,
+			"Chromatic": ChromaticColoring,
+		}
+		for name, alg := range algs {
+			c := alg(g)
+			if name != "LargestFirst" {
+				checkColoring(t, g, c, 2)
+			}
+			checkColoring(t, g, c, c.NumColors)
+		}
+	})
+
+	t.Run("Orders", func(t *testing.T) {
+		// A triangle 0 1 2 with a path 2 3 4
+		g := mockUndirectedGraph(t, 5, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {2, 3, 1}, {3, 4, 1}})
+		checkInts(t, "Largest first order", LargestFirstOrder(g), []int{2, 0, 1, 3, 4})
+		checkInts(t, "Smallest last order", SmallestLastOrder(g), []int{2, 1, 0, 3, 4})
+	})
+
+	t.Run("Chromatic", func(t *testing.T) {
+		cases := []struct {
+			name  string
+			n     int
+			edges []mockEdge
+			want  int
+		}{
+			{"Edgeless", 3, nil, 1},
+			{"Even cycle", 4, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}}, 2},
+			{"Odd cycle", 5, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 0, 1}}, 3},
+			{"Complete", 4, []mockEdge{{0, 1, 1}, {0, 2, 1}, {0, 3, 1}, {1, 2, 1}, {1, 3, 1}, {2, 3, 1}}, 4},
+			{"Wheel", 6, []mockEdge{
+				{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 0, 1},
+				{5, 0, 1}, {5, 1, 1}, {5, 2, 1}, {5, 3, 1}, {5, 4, 1},
+			}, 4},
+		}
+		for _, c := range cases {
+			g := mockUndirectedGraph(t, c.n, c.edges)
+			checkColoring(t, g, ChromaticColoring(g), c.want)
+		}
+
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 100; i++ {
+			n := 1 + r.Intn(7)
+			var edges []mockEdge
+			for a := 0; a < n; a++ {
+				for b := a + 1; b < n; b++ {
+					if r.Intn(2) == 0 {
+						edges = append(edges, mockEdge{a, b, 1})
+					}
+				}
+			}
+			g := mockUndirectedGraph(t, n, edges)
+			checkColoring(t, g, ChromaticColoring(g), bruteForceChromatic(n, edges))
+		}
+	})
+
+	t.Run("Edges", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 100; i++ {
+			n := 2 + r.Intn(9)
+			var edges []mockEdge
+			for a := 0; a < n; a++ {
+				for b := a + 1; b < n; b++ {
+					if r.Intn(2) == 0 {
+						edges = append(edges, mockEdge{a, b, 1})
+					}
+				}
+			}
+			g := mockUndirectedGraph(t, n, edges)
+			c := MisraGriesEdgeColoring(g)
+			checkEdgeColoring(t, g, c, edges)
+		}
+
+		// A directed triangle needs three colors, and its edges count once
+		g := mockGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {1, 0, 1}})
+		c := MisraGriesEdgeColoring(g)
+		checkEdgeColoring(t, g, c, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}})
+		if c.NumColors != 3 {
+			t.Fatalf(fmt.Sprintf("Triangle edges should take 3 colors but take %d", c.NumColors))
+		}
+	})
+
+	t.Run("Display", func(t *testing.T) {
+		g := mockGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 0, 1}, {1, 2, 1}})
+		ShowColoring(g, &Coloring{Colors: map[int]int{0: 0, 1: 1}, NumColors: 2})
+		for id, want := range map[int]string{
+			0: structures.Colors[DistinctColors[0]],
+			1: structures.Colors[DistinctColors[1]],
+			2: structures.Colors["orange"],
+		} {
+			n, _ := g.GetNodeByID(id)
+			data, _ := structures.ColorDataFromData(n.Extra)
+			if data.Color != want {
+				t.Fatalf(fmt.Sprintf("Node %d should be colored %s but is %s", id, want, data.Color))
+			}
+		}
+
+		ShowEdgeColoring(g, &EdgeColoring{Colors: map[EdgeKey]int{{0, 1}: 2}, NumColors: 1})
+		for k, want := range map[EdgeKey]string{
+			{0, 1}: structures.Colors[DistinctColors[2]],
+			{1, 0}: structures.Colors[DistinctColors[2]],
+		} {
+			e, _ := g.GetEdgeByNodeID(k.From, k.To)
+			data, _ := structures.ColorDataFromData(e.Extra)
+			if data.Color != want {
+				t.Fatalf(fmt.Sprintf("Edge %v should be colored %s but is %s", k, want, data.Color))
+			}
+		}
+		if e, _ := g.GetEdgeByNodeID(1, 2); e.Extra != nil {
+			t.Fatalf("Uncolored edge should not be shown")
+		}
+
+		// Colors past the palette are generated and stay different
+		seen := make(map[string]int)
+		for i := 0; i < 500; i++ {
+			c := generatedColor(i)
+			if j, ok := seen[c]; ok {
+				t.Fatalf(fmt.Sprintf("Colors %d and %d should differ but are both %s", j, i, c))
+			}
+			seen[c] = i
+		}
+	})
+}
+
+// checkColoring checks that a coloring is proper and uses the wanted number of
+// colors
+func checkColoring(t *testing.T, g *structures.Graph, c *Coloring, numColors int) {
+	t.Helper()
+	if c.NumColors != numColors {
+		t.Fatalf(fmt.Sprintf("Coloring should use %d colors but uses %d: %v", numColors, c.NumColors, c.Colors))
+	}
+	for _, n := range g.Nodes {
+		color, ok := c.Colors[n.ID]
+		if !ok || color < 0 || color >= c.NumColors {
+			t.Fatalf(fmt.Sprintf("Node %d should have a color below %d but has %d", n.ID, c.NumColors, color))
+		}
+		for _, e := range n.Edges {
+			if other := e.Nodes[1].ID; other != n.ID && c.Colors[other] == color {
+				t.Fatalf(fmt.Sprintf("Adjacent nodes %d and %d share color %d", n.ID, other, color))
+			}
+		}
+	}
+}
+
+// checkEdgeColoring checks that an edge coloring colors each of the edges, that
+// edges sharing a node have different colors and that at most one more color is
+// used than the greatest degree
+func checkEdgeColoring(t *testing.T, g *structures.Graph, c *EdgeColoring, edges []mockEdge) {
+	t.Helper()
+	degree := make(map[int]int)
+	seen := make(map[int]map[int]bool)
+	maxDegree := 0
+	for _, e := range edges {
+		color, ok := c.Colors[undirectedKeyOf(e.from, e.to)]
+		if !ok || color < 0 || color >= c.NumColors {
+			t.Fatalf(fmt.Sprintf("Edge %v should have a color below %d but has %d", e, c.NumColors, color))
+		}
+		for _, id := range []int{e.from, e.to} {
+			if seen[id] == nil {
+				seen[id] = make(map[int]bool)
+			}
+			if seen[id][color] {
+				t.Fatalf(fmt.Sprintf("Two edges at node %d share color %d", id, color))
+			}
+			seen[id][color] = true
+			degree[id]++
+			if degree[id] > maxDegree {
+				maxDegree = degree[id]
+			}
+		}
+	}
+	if len(c.Colors) != len(edges) || c.NumColors > maxDegree+1 {
+		t.Fatalf(fmt.Sprintf("Edges %v should take at most %d colors but are %v", edges, maxDegree+1, c.Colors))
+	}
+}
+
+// bruteForceChromatic returns the fewest colors needed for nodes 0 to n-1 so
+// that the ends of each edge differ
+func bruteForceChromatic(n int, edges []mockEdge) int {
+	color := make([]int, n)
+	for k := 1; ; k++ {
+		// Count through every assignment of k colors to the nodes
+		for i := range color {
+			color[i] = 0
+		}
+		for {
+			proper := true
+			for _, e := range edges {
+				if color[e.from] == color[e.to] {
+					proper = false
+					break
+				}
+			}
+			if proper {
+				return k
+			}
+			i := 0
+			for i < n && color[i] == k-1 {
+				color[i] = 0
+				i++
+			}
+			if i == n {
+				break
+			}
+			color[i]++
+		}
+	}
+}
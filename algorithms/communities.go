@@ -0,0 +1,421 @@
+package algorithms
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+const (
+	// labelPropagationRounds is the most rounds of label propagation run
+	// before the labels are taken as they are
+	labelPropagationRounds = 1000
+	// modularityEpsilon is the least modularity gain for which Louvain moves a
+	// node, so that rounding errors do not move nodes back and forth
+	modularityEpsilon = 1e-12
+)
+
+var (
+	// DistinctColors is the order in which colors are taken from the Colors
+	// palette to tell communities or colorings apart
+	DistinctColors = []string{"blue", "red", "green", "orange", "purple", "yellow", "black"}
+)
+
+// Communities is a partition of the nodes of a graph into communities along
+// with its modularity
+type Communities struct {
+	*Components
+	// Modularity is the fraction of edge weight within communities less the
+	// fraction expected if edges were placed at random with the same degrees.
+	// It ranges from -0.5 to 1
+	Modularity float64
+}
+
+// partition collects the communities of g from the label of each node
+// position. Communities are listed in order of their first node in g
+func partition(g *structures.Graph, label []int) *Components {
+	index := make(map[int]int)
+	var members [][]int
+	for i, n := range g.Nodes {
+		c, ok := index[label[i]]
+		if !ok {
+			c = len(members)
+			index[label[i]] = c
+			members = append(members, nil)
+		}
+		members[c] = append(members[c], n.ID)
+	}
+	return newComponents(members)
+}
+
+// Modularity returns the modularity of the partition c of g viewed as
+// undirected, where each edge counts its weight. Self loops are ignored, and a
+// graph without edge weight has modularity 0
+func Modularity(g *structures.Graph, c *Components) float64 {
+	edges := undirectedEdges(g)
+	m := 0.0
+	internal := make(map[int]float64)
+	degree := make(map[int]float64)
+	for _, ue := range edges {
+		w := ue.e.Weight
+		a, b := c.Membership[g.Nodes[ue.i].ID], c.Membership[g.Nodes[ue.j].ID]
+		m += w
+		degree[a] += w
+		degree[b] += w
+		if a == b {
+			internal[a] += w
+		}
+	}
+	if m == 0 {
+		return 0
+	}
+
+	q := 0.0
+	for i := range c.Members {
+		q += internal[i]/m - (degree[i]/(2*m))*(degree[i]/(2*m))
+	}
+	return q
+}
+
+// newCommunities collects the communities of g from the label of each node
+// position along with their modularity
+func newCommunities(g *structures.Graph, label []int) *Communities {
+	c := partition(g, label)
+	return &Communities{c, Modularity(g, c)}
+}
+
+// louvainGraph is a weighted undirected graph of communities built by Louvain
+type louvainGraph struct {
+	// adj holds the weight of the edges from each node to its neighbors, and
+	// self the weight of the edges within each node
+	adj  []map[int]float64
+	self []float64
+}
+
+// degree returns the total weight of the edges at node i, where edges within
+// the node count twice
+func (l *louvainGraph) degree(i int) float64 {
+	d := 2 * l.self[i]
+	for _, w := range l.adj[i] {
+		d += w
+	}
+	return d
+}
+
+// Louvain finds communities of g viewed as undirected by greedily maximizing
+// modularity in levels. Each level moves nodes one at a time into the
+// neighboring community that gains the most modularity until no move gains,
+// then merges each community into one node for the next level. Levels repeat
+// until no node moves. Edge weights must not be negative, otherwise a
+// *NegativeWeightError is returned
+func Louvain(g *structures.Graph) (*Communities, error) {
+	if err := checkWeights(g); err != nil {
+		return nil, fmt.Errorf("louvain: %w", err)
+	}
+
+	l := &louvainGraph{
+		adj:  make([]map[int]float64, len(g.Nodes)),
+		self: make([]float64, len(g.Nodes)),
+	}
+	for i := range l.adj {
+		l.adj[i] = make(map[int]float64)
+	}
+	m := 0.0
+	for _, ue := range undirectedEdges(g) {
+		l.adj[ue.i][ue.j] += ue.e.Weight
+		l.adj[ue.j][ue.i] += ue.e.Weight
+		m += ue.e.Weight
+	}
+
+	// label holds the community of each node of g, which is its node in the
+	// current level
+	label := make([]int, len(g.Nodes))
+	for i := range label {
+		label[i] = i
+	}
+	if m == 0 {
+		return newCommunities(g, label), nil
+	}
+
+	for {
+		community, moved := l.moveNodes(m)
+		if !moved {
+			break
+		}
+		var next *louvainGraph
+		next, community = l.aggregate(community)
+		for i := range label {
+			label[i] = community[label[i]]
+		}
+		l = next
+	}
+	return newCommunities(g, label), nil
+}
+
+// moveNodes moves each node of l into the neighboring community with the
+// greatest modularity gain until no move gains, given total edge weight m. It
+// returns the community of each node and whether any node moved
+func (l *louvainGraph) moveNodes(m float64) ([]int, bool) {
+	n := len(l.adj)
+	community := make([]int, n)
+	degree := make([]float64, n)
+	total := make([]float64, n)
+	for i := range community {
+		community[i] = i
+		degree[i] = l.degree(i)
+		total[i] = degree[i]
+	}
+
+	moved := false
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n; i++ {
+			// Weigh the links from i to each neighboring community
+			links := make(map[int]float64)
+			for j, w := range l.adj[i] {
+				links[community[j]] += w
+			}
+
+			// Take i out of its community, then put it back in whichever
+			// community gains the most, preferring to stay and then the
+			// lowest numbered community
+			own := community[i]
+			total[own] -= degree[i]
+			candidates := make([]int, 0, len(links))
+			for c := range links {
+				candidates = append(candidates, c)
+			}
+			sort.Ints(candidates)
+			best, bestGain := own, links[own]-total[own]*degree[i]/(2*m)
+			for _, c := range candidates {
+				if gain := links[c] - total[c]*degree[i]/(2*m); gain > bestGain+modularityEpsilon {
+					best, bestGain = c, gain
+				}
+			}
+			community[i] = best
+			total[best] += degree[i]
+			if best != own {
+				improved = true
+				moved = true
+			}
+		}
+	}
+	return community, moved
+}
+
+// aggregate builds the graph of the communities of l, numbering communities
+// from 0 in order of their first node, and returns it along with the new
+// number of the community of each node
+func (l *louvainGraph) aggregate(community []int) (*louvainGraph, []int) {
+	number := make(map[int]int)
+	renumbered := make([]int, len(community))
+	for i, c := range community {
+		if _, ok := number[c]; !ok {
+			number[c] = len(number)
+		}
+		renumbered[i] = number[c]
+	}
+
+	next := &louvainGraph{
+		adj:  make([]map[int]float64, len(number)),
+		self: make([]float64, len(number)),
+	}
+	for c := range next.adj {
+		next.adj[c] = make(map[int]float64)
+	}
+	for i := range l.adj {
+		a := renumbered[i]
+		next.self[a] += l.self[i]
+		for j, w := range l.adj[i] {
+			b := renumbered[j]
+			if a != b {
+				next.adj[a][b] += w
+			} else if i < j {
+				next.self[a] += w
+			}
+		}
+	}
+	return next, renumbered
+}
+
+// LabelPropagation finds communities of g viewed as undirected with
+// asynchronous label propagation. Every node starts with its own label, and in
+// each round the nodes, in random order, take the label of greatest total edge
+// weight among their neighbors, keeping their own label if it is among the
+// heaviest and otherwise breaking ties at random. Rounds repeat until every
+// node has a heaviest label. seed seeds the random order and tie breaks
+func LabelPropagation(g *structures.Graph, seed int64) *Communities {
+	r := rand.New(rand.NewSource(seed))
+	adj := make([]map[int]float64, len(g.Nodes))
+	for i := range adj {
+		adj[i] = make(map[int]float64)
+	}
+	for _, ue := range undirectedEdges(g) {
+		adj[ue.i][ue.j] += ue.e.Weight
+		adj[ue.j][ue.i] += ue.e.Weight
+	}
+
+	label := make([]int, len(g.Nodes))
+	for i := range label {
+		label[i] = i
+	}
+	for round := 0; round < labelPropagationRounds; round++ {
+		changed := false
+		for _, i := range r.Perm(len(label)) {
+			if len(adj[i]) == 0 {
+				continue
+			}
+			weight := make(map[int]float64)
+			for j, w := range adj[i] {
+				weight[label[j]] += w
+			}
+
+			// The neighbor labels are sorted so that random tie breaks do not
+			// depend on map order
+			candidates := make([]int, 0, len(weight))
+			for c := range weight {
+				candidates = append(candidates, c)
+			}
+			sort.Ints(candidates)
+			var best []int
+			bestWeight := 0.0
+			for _, c := range candidates {
+				w := weight[c]
+				switch {
+				case len(best) == 0 || w > bestWeight:
+					best, bestWeight = []int{c}, w
+				case w == bestWeight:
+					best = append(best, c)
+				}
+			}
+			if indexOf(best, label[i]) != -1 {
+				continue
+			}
+			label[i] = best[r.Intn(len(best))]
+			changed = true
+		}
+		if !changed {
+			break
+		}
+	}
+	return newCommunities(g, label)
+}
+
+// GirvanNewman finds communities of g viewed as undirected by repeatedly
+// removing the edge with the greatest edge betweenness, the number of shortest
+// paths between pairs of nodes that pass through it, and taking the connected
+// components left at each step as a partition. The partition of greatest
+// modularity with respect to g is returned. Betweenness counts edges, while
+// modularity counts edge weights
+func GirvanNewman(g *structures.Graph) *Communities {
+	n := len(g.Nodes)
+	edges := undirectedEdges(g)
+	adj := make([]map[int]int, n)
+	for i := range adj {
+		adj[i] = make(map[int]int)
+	}
+	for k, ue := range edges {
+		adj[ue.i][ue.j] = k
+		adj[ue.j][ue.i] = k
+	}
+
+	best := newCommunities(g, connectedLabels(adj))
+	for remaining := len(edges); remaining > 0; remaining-- {
+		between := edgeBetweenness(adj, len(edges))
+		top := -1
+		for k, b := range between {
+			if _, ok := adj[edges[k].i][edges[k].j]; ok && (top == -1 || b > between[top]) {
+				top = k
+			}
+		}
+		delete(adj[edges[top].i], edges[top].j)
+		delete(adj[edges[top].j], edges[top].i)
+
+		if c := newCommunities(g, connectedLabels(adj)); c.Modularity > best.Modularity {
+			best = c
+		}
+	}
+	return best
+}
+
+// connectedLabels labels each node position of adj with the first position of
+// its connected component
+func connectedLabels(adj []map[int]int) []int {
+	label := make([]int, len(adj))
+	for i := range label {
+		label[i] = -1
+	}
+	for root := range adj {
+		if label[root] != -1 {
+			continue
+		}
+		label[root] = root
+		queue := []int{root}
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+			for j := range adj[i] {
+				if label[j] == -1 {
+					label[j] = root
+					queue = append(queue, j)
+				}
+			}
+		}
+	}
+	return label
+}
+
+// edgeBetweenness returns the number of shortest paths between ordered pairs of
+// nodes that pass through each of the numEdges edges of adj, which maps each
+// neighbor of each node position to the number of the edge joining them.
+// Paths split between several shortest paths count fractionally
+func edgeBetweenness(adj []map[int]int, numEdges int) []float64 {
+	between := make([]float64, numEdges)
+	for s := range adj {
+		dist := make([]int, len(adj))
+		sigma := make([]float64, len(adj))
+		for i := range dist {
+			dist[i] = -1
+		}
+		dist[s], sigma[s] = 0, 1
+		order := []int{s}
+		for q := 0; q < len(order); q++ {
+			i := order[q]
+			for j := range adj[i] {
+				if dist[j] == -1 {
+					dist[j] = dist[i] + 1
+					order = append(order, j)
+				}
+				if dist[j] == dist[i]+1 {
+					sigma[j] += sigma[i]
+				}
+			}
+		}
+
+		delta := make([]float64, len(adj))
+		for q := len(order) - 1; q > 0; q-- {
+			j := order[q]
+			for i, k := range adj[j] {
+				if dist[i] == dist[j]-1 {
+					c := sigma[i] / sigma[j] * (1 + delta[j])
+					between[k] += c
+					delta[i] += c
+				}
+			}
+		}
+	}
+	return between
+}
+
+// ShowCommunities colors the nodes of g by their community in c, taking colors
+// from the Colors palette in the order of DistinctColors and generating more
+// past them. Up to 500 communities are told apart
+func ShowCommunities(g *structures.Graph, c *Components) {
+	for _, n := range g.Nodes {
+		if i, ok := c.Membership[n.ID]; ok {
+			showGeneratedColor(n, i)
+		}
+	}
+}
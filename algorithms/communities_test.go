@@ -0,0 +1,116 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestCommunities(t *testing.T) {
+	log.Printf("Testing communities")
+
+	// Two triangles 0 1 2 and 3 4 5 joined by the edge 2-3
+	triangles := func(t *testing.T) *structures.Graph {
+		return mockUndirectedGraph(t, 6, []mockEdge{
+			{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {3, 4, 1}, {4, 5, 1}, {5, 3, 1}, {2, 3, 1},
+		})
+	}
+	split := 6.0/7 - 0.5
+
+	t.Run("Modularity", func(t *testing.T) {
+		g := triangles(t)
+		checkModularity(t, "Split", Modularity(g, newComponents([][]int{{0, 1, 2}, {3, 4, 5}})), split)
+		checkModularity(t, "Whole", Modularity(g, newComponents([][]int{{0, 1, 2, 3, 4, 5}})), 0)
+		checkModularity(t, "Singletons", Modularity(g, newComponents([][]int{{0}, {1}, {2}, {3}, {4}, {5}})), -34.0/196)
+
+		g = mockUndirectedGraph(t, 2, nil)
+		checkModularity(t, "Edgeless", Modularity(g, newComponents([][]int{{0}, {1}})), 0)
+	})
+
+	algs := map[string]func(*structures.Graph) (*Communities, error){
+		"Louvain": Louvain,
+		"LabelPropagation": func(g *structures.Graph) (*Communities, error) {
+			return LabelPropagation(g, 1), nil
+		},
+		"GirvanNewman": func(g *structures.Graph) (*Communities, error) {
+			return GirvanNewman(g), nil
+		},
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			c, err := alg(triangles(t))
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find communities: %v", err))
+			}
+			checkComponents(t, c.Components, [][]int{{0, 1, 2}, {3, 4, 5}})
+			checkModularity(t, name, c.Modularity, split)
+
+			// Isolated nodes are communities of their own
+			c, _ = alg(mockUndirectedGraph(t, 3, []mockEdge{{0, 1, 1}}))
+			checkComponents(t, c.Components, [][]int{{0, 1}, {2}})
+		})
+	}
+
+	t.Run("Weighted", func(t *testing.T) {
+		// The heavy edges of a square pair its nodes
+		g := mockUndirectedGraph(t, 4, []mockEdge{{0, 1, 10}, {1, 2, 1}, {2, 3, 10}, {3, 0, 1}})
+		c, err := Louvain(g)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find communities: %v", err))
+		}
+		checkComponents(t, c.Components, [][]int{{0, 1}, {2, 3}})
+		checkComponents(t, LabelPropagation(g, 1).Components, [][]int{{0, 1}, {2, 3}})
+
+		g = mockUndirectedGraph(t, 2, []mockEdge{{0, 1, -1}})
+		var negative *NegativeWeightError
+		if _, err = Louvain(g); !errors.As(err, &negative) {
+			t.Fatalf("Louvain with a negative weight should fail with NegativeWeightError")
+		}
+	})
+
+	t.Run("Display", func(t *testing.T) {
+		g := triangles(t)
+		ShowCommunities(g, newComponents([][]int{{0, 1, 2}, {3, 4}}))
+		for id, want := range map[int]string{
+			0: structures.Colors[DistinctColors[0]],
+			2: structures.Colors[DistinctColors[0]],
+			3: structures.Colors[DistinctColors[1]],
+			5: structures.Colors["orange"],
+		} {
+			n, _ := g.GetNodeByID(id)
+			data, _ := structures.ColorDataFromData(n.Extra)
+			if data.Color != want {
+				t.Fatalf(fmt.Sprintf("Node %d should be colored %s but is %s", id, want, data.Color))
+			}
+		}
+
+		// More communities than palette colors still differ in color
+		n := 2 * len(DistinctColors)
+		members := make([][]int, n)
+		for i := range members {
+			members[i] = []int{i}
+		}
+		g = mockGraph(t, n, nil)
+		ShowCommunities(g, newComponents(members))
+		seen := make(map[string]bool)
+		for _, node := range g.Nodes {
+			data, _ := structures.ColorDataFromData(node.Extra)
+			if seen[data.Color] {
+				t.Fatalf(fmt.Sprintf("Color %s is shared by two of %d communities", data.Color, n))
+			}
+			seen[data.Color] = true
+		}
+	})
+}
+
+// checkModularity checks that a modularity is as wanted
+func checkModularity(t *testing.T, name string, q, want float64) {
+	t.Helper()
+	if math.Abs(q-want) > 1e-9 {
+		t.Fatalf(fmt.Sprintf("%s modularity should be %f but is %f", name, want, q))
+	}
+}
@@ -0,0 +1,217 @@
+package algorithms
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// Components is a partition of the nodes of a graph into components
+type Components struct {
+	// Membership maps each node ID to the index of its component
+	Membership map[int]int
+	// Members holds the node IDs of each component in increasing order
+	Members [][]int
+}
+
+// newComponents creates components from lists of member node IDs
+func newComponents(members [][]int) *Components {
+	c := &Components{
+		Membership: make(map[int]int),
+		Members:    members,
+	}
+	for i, ids := range members {
+		sort.Ints(ids)
+		for _, id := range ids {
+			c.Membership[id] = i
+		}
+	}
+	return c
+}
+
+// TarjanSCC finds the strongly connected components of g with Tarjan's
+// algorithm, which finds every component in a single depth-first search.
+// Components are listed in topological order of the condensation, so every
+// edge between two components leads to the later component
+func TarjanSCC(g *structures.Graph, v *Visitor) *Components {
+	index := make(map[int]int)
+	low := make(map[int]int)
+	onStack := make(map[int]bool)
+	var (
+		stack   []int
+		members [][]int
+	)
+
+	// enter gives n the next index and pushes it onto the component stack
+	enter := func(n *structures.Node) {
+		index[n.ID] = len(index)
+		low[n.ID] = index[n.ID]
+		stack = append(stack, n.ID)
+		onStack[n.ID] = true
+		v.discoverNode(n.ID)
+		v.examineNode(n.ID)
+	}
+
+	for _, root := range g.Nodes {
+		if _, ok := index[root.ID]; ok {
+			continue
+		}
+
+		enter(root)
+		frames := []dfsFrame{{root, 0}}
+		for len(frames) > 0 {
+			f := &frames[len(frames)-1]
+			n := f.n
+			if f.next < len(n.Edges) {
+				e := n.Edges[f.next]
+				f.next++
+				v.examineEdge(e)
+				m := e.Nodes[1].Node
+				if _, ok := index[m.ID]; !ok {
+					enter(m)
+					frames = append(frames, dfsFrame{m, 0})
+				} else if onStack[m.ID] && index[m.ID] < low[n.ID] {
+					low[n.ID] = index[m.ID]
+				}
+				continue
+			}
+
+			// A node that reaches no node above it on the stack is the first
+			// node of its component, which is every node above it
+			if low[n.ID] == index[n.ID] {
+				var component []int
+				for {
+					id := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[id] = false
+					component = append(component, id)
+					if id == n.ID {
+						break
+					}
+				}
+				members = append(members, component)
+			}
+			v.finishNode(n.ID)
+
+			frames = frames[:len(frames)-1]
+			if len(frames) > 0 {
+				p := frames[len(frames)-1].n
+				if low[n.ID] < low[p.ID] {
+					low[p.ID] = low[n.ID]
+				}
+				v.examineNode(p.ID)
+			}
+		}
+	}
+
+	// Components are completed in reverse topological order
+	for i, j := 0, len(members)-1; i < j; i, j = i+1, j-1 {
+		members[i], members[j] = members[j], members[i]
+	}
+	return newComponents(members)
+}
+
+// KosarajuSCC finds the strongly connected components of g with Kosaraju's
+// algorithm, which searches the reversed graph in decreasing order of finish
+// time of a depth-first search of g. Components are listed in topological order
+// of the condensation, so every edge between two components leads to the later
+// component
+func KosarajuSCC(g *structures.Graph, v *Visitor) *Components {
+	t, _ := DepthFirstSearch(g, v)
+	order := make([]*structures.Node, len(g.Nodes))
+	copy(order, g.Nodes)
+	sort.Slice(order, func(i, j int) bool {
+		return t.Finish[order[i].ID] > t.Finish[order[j].ID]
+	})
+
+	// The component of the unassigned node that finished last has no edges
+	// from unassigned nodes in other components, so searching the reversed
+	// graph from it reaches exactly its component
+	assigned := make(map[int]bool)
+	var members [][]int
+	for _, root := range order {
+		if assigned[root.ID] {
+			continue
+		}
+
+		assigned[root.ID] = true
+		component := []int{root.ID}
+		stack := []*structures.Node{root}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			v.examineNode(n.ID)
+			for _, e := range g.InEdges(n) {
+				v.examineEdge(e)
+				m := e.Nodes[0].Node
+				if assigned[m.ID] {
+					continue
+				}
+				assigned[m.ID] = true
+				v.discoverNode(m.ID)
+				component = append(component, m.ID)
+				stack = append(stack, m)
+			}
+			v.finishNode(n.ID)
+		}
+		members = append(members, component)
+	}
+
+	return newComponents(members)
+}
+
+// Condensation builds the condensation of g over components c, a directed
+// acyclic graph with one node per strongly connected component. The node of
+// each component has the component's index as its ID, the display data of its
+// first member and the centroid of its members' coordinates. An edge joins two
+// components if any edge of g joins their members, weighted by the least
+// weight of those edges
+func Condensation(g *structures.Graph, c *Components) (*structures.Graph, error) {
+	d := structures.NewGraphWithOrder(g.MaxEdgeWeight, g.StorageOrder())
+	for i, ids := range c.Members {
+		var (
+			centroid structures.Point
+			extra    structures.Data
+		)
+		for j, id := range ids {
+			n, err := g.GetNodeByID(id)
+			if err != nil {
+				return nil, fmt.Errorf("condensation: %w", err)
+			}
+			if j == 0 {
+				extra = n.Extra
+			}
+			centroid.X += n.Coords.X / float64(len(ids))
+			centroid.Y += n.Coords.Y / float64(len(ids))
+			centroid.Z += n.Coords.Z / float64(len(ids))
+		}
+		d.SetNodeByID(i, centroid.X, centroid.Y, centroid.Z, extra)
+	}
+
+	for _, n := range g.Nodes {
+		from, ok := c.Membership[n.ID]
+		if !ok {
+			return nil, fmt.Errorf("condensation: node %d has no component", n.ID)
+		}
+		for _, e := range n.Edges {
+			to, ok := c.Membership[e.Nodes[1].ID]
+			if !ok {
+				return nil, fmt.Errorf("condensation: node %d has no component", e.Nodes[1].ID)
+			}
+			if from == to {
+				continue
+			}
+			if old, err := d.GetEdgeByNodeID(from, to); err == nil && old.Weight <= e.Weight {
+				continue
+			}
+
+			err := d.SetEdgeByNodeID(from, to, e.Weight, e.Nodes[0].Tag, e.Nodes[1].Tag, false)
+			if err != nil {
+				return nil, fmt.Errorf("condensation: %w", err)
+			}
+		}
+	}
+
+	return d, nil
+}
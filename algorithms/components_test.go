@@ -0,0 +1,90 @@
+package algorithms
+
+import (
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	log.Printf("Testing strongly connected components")
+
+	// Components {0 1 2}, {3 4}, {5}, {6 7} and {8}
+	edges := []mockEdge{
+		{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {2, 3, 4}, {1, 3, 2}, {3, 4, 1},
+		{4, 3, 1}, {4, 5, 1}, {6, 7, 1}, {7, 6, 1}, {7, 5, 3}, {8, 8, 1},
+	}
+	want := [][]int{{0, 1, 2}, {3, 4}, {5}, {6, 7}, {8}}
+
+	algs := map[string]func(*structures.Graph, *Visitor) *Components{
+		"Tarjan":   TarjanSCC,
+		"Kosaraju": KosarajuSCC,
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			g := mockGraph(t, 9, edges)
+			c := alg(g, nil)
+			checkComponents(t, c, want)
+
+			// Components are in topological order
+			for _, n := range g.Nodes {
+				for _, e := range n.Edges {
+					if c.Membership[e.Nodes[0].ID] > c.Membership[e.Nodes[1].ID] {
+						t.Fatalf(fmt.Sprintf("Components %v are not in topological order", c.Members))
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("Condensation", func(t *testing.T) {
+		g := mockGraph(t, 9, edges)
+		c := TarjanSCC(g, nil)
+		d, err := Condensation(g, c)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not build condensation: %v", err))
+		}
+		if len(d.Nodes) != len(c.Members) {
+			t.Fatalf(fmt.Sprintf("Condensation should have %d nodes but has %d", len(c.Members), len(d.Nodes)))
+		}
+
+		// Nodes lie along the x axis, so centroids are the mean node ID
+		for i, ids := range c.Members {
+			n, _ := d.GetNodeByID(i)
+			x := 0.0
+			for _, id := range ids {
+				x += float64(id) / float64(len(ids))
+			}
+			if n.Coords.X != x {
+				t.Fatalf(fmt.Sprintf("Component %v should be placed at %f but is at %f", ids, x, n.Coords.X))
+			}
+		}
+
+		// {0 1 2} -> {3 4} keeps the lighter of its two edges
+		from, to := c.Membership[0], c.Membership[3]
+		e, err := d.GetEdgeByNodeID(from, to)
+		if err != nil || e.Weight != 2 {
+			t.Fatalf(fmt.Sprintf("Condensation should have an edge of weight %f from %d to %d", 2.0, from, to))
+		}
+		if d.NumEdges != 3 {
+			t.Fatalf(fmt.Sprintf("Condensation should have %d edges but has %d", 3, d.NumEdges))
+		}
+		if dc := TarjanSCC(d, nil); len(dc.Members) != len(d.Nodes) {
+			t.Fatalf("Condensation should be acyclic")
+		}
+	})
+}
+
+// checkComponents checks that c holds the wanted components in any order
+func checkComponents(t *testing.T, c *Components, want [][]int) {
+	t.Helper()
+	if len(c.Members) != len(want) {
+		t.Fatalf(fmt.Sprintf("Components should be %v but are %v", want, c.Members))
+	}
+	for _, ids := range want {
+		i := c.Membership[ids[0]]
+		checkInts(t, fmt.Sprintf("Component of %d", ids[0]), c.Members[i], ids)
+	}
+}
@@ -0,0 +1,251 @@
+package algorithms
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// CycleError states that an algorithm requiring a directed acyclic graph found
+// a cycle
+// Cycle holds the node IDs of the cycle in edge order. The last node has an
+// edge back to the first
+type CycleError struct {
+	Cycle []int
+	Err   error
+}
+
+// Error serves the error message for CycleError
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("Cycle through nodes %v: %v", e.Cycle, e.Err)
+}
+
+func (e *CycleError) Unwrap() error { return e.Err }
+
+// TopologicalSort orders the nodes of g so that every edge leads from an
+// earlier node to a later one, using Kahn's algorithm, which repeatedly removes
+// a node without incoming edges. Ties are broken by the order of g's nodes. If
+// g has a cycle, a *CycleError holding the cycle is returned
+func TopologicalSort(g *structures.Graph, v *Visitor) ([]int, error) {
+	indegree := make(map[int]int, len(g.Nodes))
+	var queue []*structures.Node
+	for _, n := range g.Nodes {
+		indegree[n.ID] = len(g.InEdges(n))
+		if indegree[n.ID] == 0 {
+			queue = append(queue, n)
+			v.discoverNode(n.ID)
+		}
+	}
+
+	order := make([]int, 0, len(g.Nodes))
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		order = append(order, n.ID)
+		v.examineNode(n.ID)
+
+		for _, e := range n.Edges {
+			v.examineEdge(e)
+			m := e.Nodes[1].Node
+			indegree[m.ID]--
+			if indegree[m.ID] == 0 {
+				queue = append(queue, m)
+				v.discoverNode(m.ID)
+			}
+		}
+		v.finishNode(n.ID)
+	}
+
+	if len(order) < len(g.Nodes) {
+		return nil, &CycleError{FindCycle(g), nil}
+	}
+	return order, nil
+}
+
+// TopologicalSortDFS orders the nodes of g so that every edge leads from an
+// earlier node to a later one, using the reverse finish order of a depth-first
+// search. If g has a cycle, a *CycleError holding the cycle is returned
+func TopologicalSortDFS(g *structures.Graph, v *Visitor) ([]int, error) {
+	t, _ := DepthFirstSearch(g, v)
+	if cycle := backEdgeCycle(g, t); cycle != nil {
+		return nil, &CycleError{cycle, nil}
+	}
+
+	order := make([]int, len(t.Order))
+	copy(order, t.Order)
+	sort.Slice(order, func(i, j int) bool {
+		return t.Finish[order[i]] > t.Finish[order[j]]
+	})
+	return order, nil
+}
+
+// FindCycle returns a cycle of g in edge order, or nil if g is acyclic
+func FindCycle(g *structures.Graph) []int {
+	t, _ := DepthFirstSearch(g, nil)
+	return backEdgeCycle(g, t)
+}
+
+// backEdgeCycle returns the cycle closed by the first back edge of depth-first
+// traversal t of g, or nil if there is none
+func backEdgeCycle(g *structures.Graph, t *Traversal) []int {
+	for _, n := range g.Nodes {
+		for _, e := range n.Edges {
+			k := KeyOf(e)
+			if t.Edges[k] != BackEdge {
+				continue
+			}
+
+			// The far node of a back edge is an ancestor of its near node
+			cycle := []int{k.From}
+			for id := k.From; id != k.To; {
+				id = t.Parent[id]
+				cycle = append(cycle, id)
+			}
+			reverseInts(cycle)
+			return cycle
+		}
+	}
+	return nil
+}
+
+// LongestPath finds the critical path of directed acyclic graph g, the path of
+// greatest total weight between any two nodes. If g has a cycle, a *CycleError
+// holding the cycle is returned
+func LongestPath(g *structures.Graph, v *Visitor) (*Path, error) {
+	order, err := TopologicalSort(g, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(order) == 0 {
+		return &Path{}, nil
+	}
+
+	// Every node starts a path of its own, so costs begin at zero
+	dist := make(map[int]float64, len(order))
+	parent := make(map[int]int)
+	end := order[0]
+	for _, id := range order {
+		n, _ := g.GetNodeByID(id)
+		v.examineNode(id)
+		for _, e := range n.Edges {
+			v.examineEdge(e)
+			m := e.Nodes[1].ID
+			if d := dist[id] + e.Weight; d > dist[m] {
+				dist[m] = d
+				parent[m] = id
+			}
+		}
+		v.finishNode(id)
+
+		if dist[id] > dist[end] {
+			end = id
+		}
+	}
+
+	nodes := []int{end}
+	for p, ok := parent[end]; ok; p, ok = parent[p] {
+		nodes = append(nodes, p)
+	}
+	reverseInts(nodes)
+	return &Path{nodes, dist[end]}, nil
+}
+
+// TransitiveClosure returns a graph with the nodes of g and an edge from each
+// node to every node reachable from it, including itself if it lies on a cycle.
+// Edges of g keep their weight and tags. Other edges have zero weight and take
+// the tags of the first and last edges of a path they replace
+func TransitiveClosure(g *structures.Graph) (*structures.Graph, error) {
+	c := copyNodes(g)
+	for _, n := range g.Nodes {
+		// first and last hold the first and last edges of a path from n to
+		// each reached node
+		first := make(map[int]*structures.Edge)
+		last := make(map[int]*structures.Edge)
+		var queue []*structures.Node
+		for _, e := range n.Edges {
+			m := e.Nodes[1].Node
+			if _, ok := first[m.ID]; !ok {
+				first[m.ID] = e
+				last[m.ID] = e
+				queue = append(queue, m)
+			}
+		}
+		for len(queue) > 0 {
+			m := queue[0]
+			queue = queue[1:]
+			for _, e := range m.Edges {
+				r := e.Nodes[1].Node
+				if _, ok := first[r.ID]; !ok {
+					first[r.ID] = first[m.ID]
+					last[r.ID] = e
+					queue = append(queue, r)
+				}
+			}
+		}
+
+		for _, r := range g.Nodes {
+			f, ok := first[r.ID]
+			if !ok {
+				continue
+			}
+
+			w := 0.0
+			if e, err := g.GetEdge(n, r.ID); err == nil {
+				w = e.Weight
+				f = e
+				last[r.ID] = e
+			}
+			err := c.SetEdgeByNodeID(n.ID, r.ID, w, f.Nodes[0].Tag, last[r.ID].Nodes[1].Tag, false)
+			if err != nil {
+				return nil, fmt.Errorf("transitive closure: %w", err)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// TransitiveReduction returns a graph with the nodes of directed acyclic graph
+// g and the fewest edges of g that keep every node reachable from the same
+// nodes. An edge is kept unless its far node can also be reached through
+// another path. If g has a cycle, a *CycleError holding the cycle is returned
+func TransitiveReduction(g *structures.Graph) (*structures.Graph, error) {
+	if cycle := FindCycle(g); cycle != nil {
+		return nil, &CycleError{cycle, nil}
+	}
+
+	r := copyNodes(g)
+	for _, n := range g.Nodes {
+		// Mark the nodes reachable from n by paths of two or more edges
+		indirect := make(map[int]bool)
+		var stack []*structures.Node
+		for _, e := range n.Edges {
+			stack = append(stack, e.Nodes[1].Node)
+		}
+		for len(stack) > 0 {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, e := range m.Edges {
+				if id := e.Nodes[1].ID; !indirect[id] {
+					indirect[id] = true
+					stack = append(stack, e.Nodes[1].Node)
+				}
+			}
+		}
+
+		for _, e := range n.Edges {
+			if indirect[e.Nodes[1].ID] {
+				continue
+			}
+			err := r.SetEdgeByNodeID(
+				n.ID, e.Nodes[1].ID, e.Weight, e.Nodes[0].Tag, e.Nodes[1].Tag, false,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("transitive reduction: %w", err)
+			}
+		}
+	}
+
+	return r, nil
+}
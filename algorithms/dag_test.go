@@ -0,0 +1,161 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestDAG(t *testing.T) {
+	log.Printf("Testing directed acyclic graph utilities")
+
+	// A build pipeline where 0 and 1 are independent inputs
+	edges := []mockEdge{
+		{0, 2, 3}, {1, 2, 1}, {1, 3, 4}, {2, 4, 2}, {3, 4, 1}, {4, 5, 2},
+		{2, 5, 1}, {0, 5, 1},
+	}
+	cycleEdges := append([]mockEdge{{5, 2, 1}}, edges...)
+
+	algs := map[string]func(*structures.Graph, *Visitor) ([]int, error){
+		"Kahn": TopologicalSort,
+		"DFS":  TopologicalSortDFS,
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			g := mockGraph(t, 7, edges)
+			order, err := alg(g, nil)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not sort graph: %v", err))
+			}
+			checkTopologicalOrder(t, g, order)
+
+			g = mockGraph(t, 7, cycleEdges)
+			_, err = alg(g, nil)
+			var cycle *CycleError
+			if !errors.As(err, &cycle) {
+				t.Fatalf("Sorting a graph with a cycle should fail with CycleError")
+			}
+			checkCycle(t, g, cycle.Cycle)
+		})
+	}
+
+	t.Run("Kahn order", func(t *testing.T) {
+		g := mockGraph(t, 7, edges)
+		order, _ := TopologicalSort(g, nil)
+		checkInts(t, "Topological order", order, []int{0, 1, 6, 2, 3, 4, 5})
+	})
+
+	t.Run("Find cycle", func(t *testing.T) {
+		g := mockGraph(t, 7, cycleEdges)
+		checkCycle(t, g, FindCycle(g))
+
+		g = mockGraph(t, 7, []mockEdge{{3, 3, 1}})
+		checkInts(t, "Self loop cycle", FindCycle(g), []int{3})
+
+		g = mockGraph(t, 7, edges)
+		if cycle := FindCycle(g); cycle != nil {
+			t.Fatalf(fmt.Sprintf("Graph is acyclic but found cycle %v", cycle))
+		}
+	})
+
+	t.Run("Longest path", func(t *testing.T) {
+		g := mockGraph(t, 7, edges)
+		p, err := LongestPath(g, nil)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find longest path: %v", err))
+		}
+		checkInts(t, "Longest path", p.Nodes, []int{0, 2, 4, 5})
+		checkPathCost(t, g, p)
+		if p.Cost != 7 {
+			t.Fatalf(fmt.Sprintf("Longest path should cost %f but costs %f", 7.0, p.Cost))
+		}
+
+		var cycle *CycleError
+		if _, err = LongestPath(mockGraph(t, 7, cycleEdges), nil); !errors.As(err, &cycle) {
+			t.Fatalf("Longest path of a graph with a cycle should fail with CycleError")
+		}
+	})
+
+	t.Run("Transitive closure", func(t *testing.T) {
+		g := mockGraph(t, 7, edges)
+		c, err := TransitiveClosure(g)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find transitive closure: %v", err))
+		}
+		reach := map[int][]int{0: {2, 4, 5}, 1: {2, 3, 4, 5}, 2: {4, 5}, 3: {4, 5}, 4: {5}}
+		checkReachability(t, c, reach)
+		if e, _ := c.GetEdgeByNodeID(1, 3); e.Weight != 4 {
+			t.Fatalf("Closure should keep the weights of the graph's edges")
+		}
+
+		g = mockGraph(t, 7, cycleEdges)
+		c, _ = TransitiveClosure(g)
+		if _, err = c.GetEdgeByNodeID(2, 2); err != nil {
+			t.Fatalf("Closure should have self loops for nodes on cycles")
+		}
+	})
+
+	t.Run("Transitive reduction", func(t *testing.T) {
+		g := mockGraph(t, 7, edges)
+		r, err := TransitiveReduction(g)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find transitive reduction: %v", err))
+		}
+		reduced := map[int][]int{0: {2}, 1: {2, 3}, 2: {4}, 3: {4}, 4: {5}}
+		checkReachability(t, r, reduced)
+
+		var cycle *CycleError
+		if _, err = TransitiveReduction(mockGraph(t, 7, cycleEdges)); !errors.As(err, &cycle) {
+			t.Fatalf("Reducing a graph with a cycle should fail with CycleError")
+		}
+	})
+}
+
+// checkTopologicalOrder checks that order holds every node of g and that every
+// edge leads forward in it
+func checkTopologicalOrder(t *testing.T, g *structures.Graph, order []int) {
+	t.Helper()
+	pos := make(map[int]int)
+	for i, id := range order {
+		pos[id] = i
+	}
+	if len(pos) != len(g.Nodes) {
+		t.Fatalf(fmt.Sprintf("Order %v should hold all %d nodes", order, len(g.Nodes)))
+	}
+	for _, n := range g.Nodes {
+		for _, e := range n.Edges {
+			if pos[e.Nodes[0].ID] >= pos[e.Nodes[1].ID] {
+				t.Fatalf(fmt.Sprintf("Edge %v leads backward in order %v", KeyOf(e), order))
+			}
+		}
+	}
+}
+
+// checkCycle checks that cycle follows the edges of g
+func checkCycle(t *testing.T, g *structures.Graph, cycle []int) {
+	t.Helper()
+	if len(cycle) == 0 {
+		t.Fatalf("Cycle should not be empty")
+	}
+	for i, id := range cycle {
+		if _, err := g.GetEdgeByNodeID(id, cycle[(i+1)%len(cycle)]); err != nil {
+			t.Fatalf(fmt.Sprintf("Cycle %v should follow graph edges", cycle))
+		}
+	}
+}
+
+// checkReachability checks that the edges of g lead from each node to exactly
+// the nodes listed for it
+func checkReachability(t *testing.T, g *structures.Graph, want map[int][]int) {
+	t.Helper()
+	for _, n := range g.Nodes {
+		var got []int
+		for _, e := range n.Edges {
+			got = append(got, e.Nodes[1].ID)
+		}
+		checkInts(t, fmt.Sprintf("Edges from %d", n.ID), got, want[n.ID])
+	}
+}
@@ -0,0 +1,469 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// DegreeError states that the degrees of some nodes rule out an Eulerian trail
+// or circuit
+type DegreeError struct {
+	// Nodes holds the IDs of the nodes of odd degree, or of unequal in-degree
+	// and out-degree if the graph is directed
+	Nodes    []int
+	directed bool
+	circuit  bool
+	Err      error
+}
+
+// Error serves the error message for DegreeError
+func (e *DegreeError) Error() string {
+	allowed := "none"
+	if !e.circuit {
+		allowed = "at most 2"
+	}
+	if e.directed {
+		return fmt.Sprintf(
+			"Nodes %v have unequal in-degree and out-degree, but %s may: %v",
+			e.Nodes, allowed, e.Err,
+		)
+	}
+	return fmt.Sprintf("Nodes %v have odd degree, but %s may: %v", e.Nodes, allowed, e.Err)
+}
+
+func (e *DegreeError) Unwrap() error { return e.Err }
+
+// DisconnectedError states that the edges of a graph are split between
+// components that no trail can join
+type DisconnectedError struct {
+	// Components holds the node IDs of each component with edges
+	Components [][]int
+	strongly   bool
+	Err        error
+}
+
+// Error serves the error message for DisconnectedError
+func (e *DisconnectedError) Error() string {
+	kind := "connected"
+	if e.strongly {
+		kind = "strongly connected"
+	}
+	return fmt.Sprintf("Edges are split between %d %s components %v: %v", len(e.Components), kind, e.Components, e.Err)
+}
+
+func (e *DisconnectedError) Unwrap() error { return e.Err }
+
+// Postman is a shortest closed walk through every edge of a graph
+type Postman struct {
+	// Circuit holds the walk, which returns to its first node, and its cost
+	Circuit *Path
+	// Duplicated holds the keys of the edges walked more than once, listed
+	// once for each extra time they are walked
+	Duplicated []EdgeKey
+}
+
+// eulerGraph is the multigraph of edges to be walked by an Eulerian trail
+type eulerGraph struct {
+	g        *structures.Graph
+	directed bool
+	// ends holds the node positions at the ends of each edge, from first to
+	// second if directed, and edges the graph edge walked for each
+	ends  [][2]int
+	edges []*structures.Edge
+}
+
+// newEulerGraph creates the multigraph of the edges of g. If directed is not
+// set, nodes joined in both directions are joined by one edge, the heaviest
+func newEulerGraph(g *structures.Graph, directed bool) *eulerGraph {
+	eg := &eulerGraph{g: g, directed: directed}
+	if directed {
+		index := make(map[int]int, len(g.Nodes))
+		for i, n := range g.Nodes {
+			index[n.ID] = i
+		}
+		for i, n := range g.Nodes {
+			for _, e := range n.Edges {
+				eg.add(i, index[e.Nodes[1].ID], e)
+			}
+		}
+		return eg
+	}
+
+	for _, ue := range undirectedEdges(g) {
+		eg.add(ue.i, ue.j, ue.e)
+	}
+	for i, n := range g.Nodes {
+		for _, e := range n.Edges {
+			if e.Nodes[1].ID == n.ID {
+				eg.add(i, i, e)
+			}
+		}
+	}
+	return eg
+}
+
+// add adds an edge from node position i to j walked along e
+func (eg *eulerGraph) add(i, j int, e *structures.Edge) {
+	eg.ends = append(eg.ends, [2]int{i, j})
+	eg.edges = append(eg.edges, e)
+}
+
+// balance returns the out-degree less the in-degree of each node position if
+// the multigraph is directed, and otherwise the degree, where self loops count
+// twice
+func (eg *eulerGraph) balance() []int {
+	b := make([]int, len(eg.g.Nodes))
+	for _, e := range eg.ends {
+		if eg.directed {
+			b[e[0]]++
+			b[e[1]]--
+		} else {
+			b[e[0]]++
+			b[e[1]]++
+		}
+	}
+	return b
+}
+
+// components returns the node IDs of each connected component of the
+// multigraph, ignoring direction, that has edges
+func (eg *eulerGraph) components() [][]int {
+	u := newUnionFind()
+	for _, e := range eg.ends {
+		u.union(eg.g.Nodes[e[0]].ID, eg.g.Nodes[e[1]].ID)
+	}
+	index := make(map[int]int)
+	var members [][]int
+	for _, n := range eg.g.Nodes {
+		if _, ok := u.parent[n.ID]; !ok {
+			continue
+		}
+		root := u.find(n.ID)
+		c, ok := index[root]
+		if !ok {
+			c = len(members)
+			index[root] = c
+			members = append(members, nil)
+		}
+		members[c] = append(members[c], n.ID)
+	}
+	return newComponents(members).Members
+}
+
+// start checks that the multigraph has an Eulerian trail, or circuit if
+// circuit is set, and returns the node position the trail starts from. If the
+// degrees rule out a trail, a *DegreeError is returned, and if the edges are
+// not connected, a *DisconnectedError
+func (eg *eulerGraph) start(circuit bool) (int, error) {
+	if len(eg.ends) == 0 {
+		return 0, nil
+	}
+
+	// A trail that is not a circuit starts from the node with an extra
+	// outgoing edge, or either node of odd degree
+	start, startFound := eg.ends[0][0], false
+	var unbalanced []int
+	balanced := true
+	for i, b := range eg.balance() {
+		if eg.directed && b == 0 || !eg.directed && b%2 == 0 {
+			continue
+		}
+		unbalanced = append(unbalanced, eg.g.Nodes[i].ID)
+		if eg.directed && (b > 1 || b < -1) {
+			balanced = false
+		}
+		if !startFound && (!eg.directed || b == 1) {
+			start, startFound = i, true
+		}
+	}
+	if !balanced || len(unbalanced) > 2 || circuit && len(unbalanced) > 0 {
+		return 0, &DegreeError{unbalanced, eg.directed, circuit, nil}
+	}
+
+	if c := eg.components(); len(c) > 1 {
+		return 0, &DisconnectedError{c, false, nil}
+	}
+	return start, nil
+}
+
+// walk returns the trail from node position start through every edge of the
+// multigraph exactly once, which must exist
+func (eg *eulerGraph) walk(v *Visitor, start int) *Path {
+	p := &Path{}
+	for _, i := range eulerWalk(len(eg.g.Nodes), eg.ends, eg.directed, start, func(k int) {
+		v.examineEdge(eg.edges[k])
+		p.Cost += eg.edges[k].Weight
+	}) {
+		p.Nodes = append(p.Nodes, eg.g.Nodes[i].ID)
+	}
+	return p
+}
+
+// eulerWalk returns a walk from node position start through each edge of a
+// multigraph of n nodes exactly once, given the positions at the ends of each
+// edge. If directed is set, edges are only walked from their first end. It
+// follows Hierholzer's algorithm, which walks until it is stuck and then
+// splices in further closed walks from nodes along the way with edges left.
+// visit, if not nil, is called with the number of each edge as it is walked.
+// The walk leaves out any edges it cannot reach
+func eulerWalk(n int, ends [][2]int, directed bool, start int, visit func(k int)) []int {
+	at := make([][]int, n)
+	for k, e := range ends {
+		at[e[0]] = append(at[e[0]], k)
+		if !directed && e[1] != e[0] {
+			at[e[1]] = append(at[e[1]], k)
+		}
+	}
+	used := make([]bool, len(ends))
+	next := make([]int, n)
+
+	var walk []int
+	stack := []int{start}
+	for len(stack) > 0 {
+		x := stack[len(stack)-1]
+		for next[x] < len(at[x]) && used[at[x][next[x]]] {
+			next[x]++
+		}
+		if next[x] == len(at[x]) {
+			walk = append(walk, x)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		k := at[x][next[x]]
+		used[k] = true
+		if visit != nil {
+			visit(k)
+		}
+		y := ends[k][1]
+		if y == x {
+			y = ends[k][0]
+		}
+		stack = append(stack, y)
+	}
+	reverseInts(walk)
+	return walk
+}
+
+// EulerianTrail finds a trail through every edge of g exactly once with
+// Hierholzer's algorithm. If directed is not set, g is viewed as undirected, so
+// that nodes joined in both directions are joined by a single edge. The trail
+// is a circuit if every node has even degree, or equal in-degree and
+// out-degree, and otherwise runs between the two nodes that do not. If more
+// nodes do not, a *DegreeError is returned, and if the edges are not all
+// connected, a *DisconnectedError. A graph without edges has a trail of its
+// first node
+func EulerianTrail(g *structures.Graph, v *Visitor, directed bool) (*Path, error) {
+	eg := newEulerGraph(g, directed)
+	start, err := eg.start(false)
+	if err != nil {
+		return nil, fmt.Errorf("eulerian trail: %w", err)
+	}
+	if len(g.Nodes) == 0 {
+		return &Path{}, nil
+	}
+	return eg.walk(v, start), nil
+}
+
+// EulerianCircuit finds a closed trail through every edge of g exactly once
+// with Hierholzer's algorithm. If directed is not set, g is viewed as
+// undirected, so that nodes joined in both directions are joined by a single
+// edge. Every node must have even degree, or equal in-degree and out-degree,
+// otherwise a *DegreeError is returned, and the edges must all be connected,
+// otherwise a *DisconnectedError is returned. A graph without edges has a
+// circuit of its first node
+func EulerianCircuit(g *structures.Graph, v *Visitor, directed bool) (*Path, error) {
+	eg := newEulerGraph(g, directed)
+	start, err := eg.start(true)
+	if err != nil {
+		return nil, fmt.Errorf("eulerian circuit: %w", err)
+	}
+	if len(g.Nodes) == 0 {
+		return &Path{}, nil
+	}
+	return eg.walk(v, start), nil
+}
+
+// ChinesePostman finds a shortest closed walk through every edge of g at least
+// once by duplicating the lightest set of edges that makes g Eulerian and
+// walking an Eulerian circuit. If directed is not set, g is viewed as
+// undirected, and the nodes of odd degree are paired by a minimum weight
+// perfect matching of their shortest path distances, then the edges along
+// the paths are duplicated. If directed is set, a minimum cost flow from nodes
+// with more incoming edges to nodes with more outgoing edges gives the number
+// of times to duplicate each edge. Edge weights must not be negative,
+// otherwise a *NegativeWeightError is returned. The edges must all be
+// connected, or strongly connected if directed is set, otherwise a
+// *DisconnectedError is returned
+func ChinesePostman(g *structures.Graph, v *Visitor, directed bool) (*Postman, error) {
+	if err := checkWeights(g); err != nil {
+		return nil, fmt.Errorf("chinese postman: %w", err)
+	}
+	eg := newEulerGraph(g, directed)
+	if len(eg.ends) == 0 {
+		p := &Postman{Circuit: &Path{}}
+		if len(g.Nodes) > 0 {
+			p.Circuit.Nodes = []int{g.Nodes[0].ID}
+		}
+		return p, nil
+	}
+
+	var (
+		duplicated []int
+		err        error
+	)
+	if directed {
+		duplicated, err = eg.directedDuplicates()
+	} else {
+		duplicated, err = eg.undirectedDuplicates()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("chinese postman: %w", err)
+	}
+
+	p := &Postman{}
+	for _, k := range duplicated {
+		eg.add(eg.ends[k][0], eg.ends[k][1], eg.edges[k])
+		p.Duplicated = append(p.Duplicated, KeyOf(eg.edges[k]))
+	}
+	p.Circuit = eg.walk(v, eg.ends[0][0])
+	return p, nil
+}
+
+// undirectedDuplicates returns the numbers of the edges to duplicate so that
+// every node of the undirected multigraph has even degree, listing edges
+// duplicated more than once as often
+func (eg *eulerGraph) undirectedDuplicates() ([]int, error) {
+	if c := eg.components(); len(c) > 1 {
+		return nil, &DisconnectedError{c, false, nil}
+	}
+
+	n := len(eg.g.Nodes)
+	at := make([][]int, n)
+	for k, e := range eg.ends {
+		at[e[0]] = append(at[e[0]], k)
+		at[e[1]] = append(at[e[1]], k)
+	}
+	var odd []int
+	for i, b := range eg.balance() {
+		if b%2 == 1 {
+			odd = append(odd, i)
+		}
+	}
+
+	// Dijkstra from each odd node gives the distances to pair them by and
+	// the edges along the shortest paths
+	dist := make([][]float64, n)
+	via := make([][]int, n)
+	for _, s := range odd {
+		dist[s] = make([]float64, n)
+		via[s] = make([]int, n)
+		for i := range dist[s] {
+			dist[s][i] = math.Inf(1)
+			via[s][i] = -1
+		}
+		dist[s][s] = 0
+		q := &priorityQueue{}
+		q.push(s, 0)
+		for q.Len() > 0 {
+			item := q.pop()
+			x := item.id
+			if item.priority > dist[s][x] {
+				continue
+			}
+			for _, k := range at[x] {
+				y := eg.ends[k][0] + eg.ends[k][1] - x
+				if d := dist[s][x] + eg.edges[k].Weight; d < dist[s][y] {
+					dist[s][y] = d
+					via[s][y] = k
+					q.push(y, d)
+				}
+			}
+		}
+	}
+
+	var duplicated []int
+	for _, pair := range minWeightPerfectMatching(odd, dist) {
+		s := pair[0]
+		for y := pair[1]; y != s; {
+			k := via[s][y]
+			duplicated = append(duplicated, k)
+			y = eg.ends[k][0] + eg.ends[k][1] - y
+		}
+	}
+	return duplicated, nil
+}
+
+// directedDuplicates returns the numbers of the edges to duplicate so that
+// every node of the directed multigraph has equal in-degree and out-degree,
+// listing edges duplicated more than once as often
+func (eg *eulerGraph) directedDuplicates() ([]int, error) {
+	g := eg.g
+	var strong [][]int
+	hasEdges := make(map[int]bool)
+	for _, e := range eg.ends {
+		hasEdges[g.Nodes[e[0]].ID] = true
+		hasEdges[g.Nodes[e[1]].ID] = true
+	}
+	for _, c := range TarjanSCC(g, nil).Members {
+		if hasEdges[c[0]] {
+			strong = append(strong, c)
+		}
+	}
+	if len(strong) > 1 {
+		return nil, &DisconnectedError{newComponents(strong).Members, true, nil}
+	}
+
+	// Flow from a new source to nodes with more incoming edges, along the
+	// edges of g at the cost of their weight, to a new sink from nodes with
+	// more outgoing edges
+	balance := eg.balance()
+	source, sink := 0, 0
+	for _, n := range g.Nodes {
+		if n.ID >= source {
+			source = n.ID + 1
+		}
+	}
+	sink = source + 1
+	h := structures.NewGraph(math.Inf(1))
+	for _, n := range g.Nodes {
+		h.SetNodeByID(n.ID, 0, 0, 0, nil)
+	}
+	h.SetNodeByID(source, 0, 0, 0, nil)
+	h.SetNodeByID(sink, 0, 0, 0, nil)
+	total := 0
+	for i, n := range g.Nodes {
+		switch b := balance[i]; {
+		case b < 0:
+			h.SetEdgeByNodeID(source, n.ID, float64(-b), "", "", false)
+			total -= b
+		case b > 0:
+			h.SetEdgeByNodeID(n.ID, sink, float64(b), "", "", false)
+		}
+	}
+	if total == 0 {
+		return nil, nil
+	}
+	for k, e := range eg.ends {
+		if e[0] == e[1] {
+			continue
+		}
+		a, b := g.Nodes[e[0]].ID, g.Nodes[e[1]].ID
+		h.SetEdgeByNodeID(a, b, float64(total), "", "", false)
+		h.SetEdgeCostByNodeID(a, b, eg.edges[k].Weight, false)
+	}
+
+	f, err := SuccessiveShortestPaths(h, nil, source, sink)
+	if err != nil {
+		return nil, err
+	}
+	var duplicated []int
+	for k, e := range eg.ends {
+		flow := f.EdgeFlow[EdgeKey{g.Nodes[e[0]].ID, g.Nodes[e[1]].ID}]
+		for times := int(math.Round(flow)); times > 0 && e[0] != e[1]; times-- {
+			duplicated = append(duplicated, k)
+		}
+	}
+	return duplicated, nil
+}
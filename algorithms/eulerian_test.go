@@ -0,0 +1,216 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestEulerian(t *testing.T) {
+	log.Printf("Testing Eulerian trails")
+
+	t.Run("Undirected", func(t *testing.T) {
+		// Two triangles sharing node 2 form a circuit
+		edges := []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {2, 3, 2}, {3, 4, 2}, {4, 2, 2}}
+		g := mockUndirectedGraph(t, 5, edges)
+		p, err := EulerianCircuit(g, nil, false)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find Eulerian circuit: %v", err))
+		}
+		checkTrail(t, p, edges, false, 9)
+		if p.Nodes[0] != p.Nodes[len(p.Nodes)-1] {
+			t.Fatalf(fmt.Sprintf("Circuit %v should return to its first node", p.Nodes))
+		}
+
+		// Removing edge 0-1 leaves a trail between nodes 0 and 1
+		edges = edges[1:]
+		g = mockUndirectedGraph(t, 5, edges)
+		p, err = EulerianTrail(g, nil, false)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find Eulerian trail: %v", err))
+		}
+		checkTrail(t, p, edges, false, 8)
+		if ends := []int{p.Nodes[0], p.Nodes[len(p.Nodes)-1]}; ends[0]+ends[1] != 1 {
+			t.Fatalf(fmt.Sprintf("Trail %v should run between nodes 0 and 1", p.Nodes))
+		}
+
+		var degree *DegreeError
+		if _, err = EulerianCircuit(g, nil, false); !errors.As(err, &degree) {
+			t.Fatalf("Circuit with nodes of odd degree should fail with DegreeError")
+		}
+		checkInts(t, "Odd degree nodes", degree.Nodes, []int{0, 1})
+
+		// Self loops count twice toward the degree of their node
+		edges = []mockEdge{{0, 1, 1}, {1, 1, 1}}
+		p, err = EulerianTrail(mockUndirectedGraph(t, 2, edges), nil, false)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find Eulerian trail with a self loop: %v", err))
+		}
+		checkTrail(t, p, edges, false, 2)
+	})
+
+	t.Run("Directed", func(t *testing.T) {
+		edges := []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {0, 3, 1}, {3, 0, 1}}
+		g := mockGraph(t, 4, edges)
+		p, err := EulerianCircuit(g, nil, true)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find Eulerian circuit: %v", err))
+		}
+		checkTrail(t, p, edges, true, 5)
+
+		// Without edge 3-0, node 0 has an extra outgoing edge and node 3 an
+		// extra incoming edge, so the trail runs from 0 to 3
+		edges = edges[:4]
+		g = mockGraph(t, 4, edges)
+		p, err = EulerianTrail(g, nil, true)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find Eulerian trail: %v", err))
+		}
+		checkTrail(t, p, edges, true, 4)
+		if p.Nodes[0] != 0 || p.Nodes[len(p.Nodes)-1] != 3 {
+			t.Fatalf(fmt.Sprintf("Trail %v should run from node 0 to node 3", p.Nodes))
+		}
+
+		var degree *DegreeError
+		g = mockGraph(t, 3, []mockEdge{{0, 1, 1}, {0, 2, 1}})
+		if _, err = EulerianTrail(g, nil, true); !errors.As(err, &degree) {
+			t.Fatalf("Trail from a node with two extra outgoing edges should fail with DegreeError")
+		}
+		if _, err = EulerianTrail(g, nil, false); err != nil {
+			t.Fatalf(fmt.Sprintf("Undirected trail should ignore edge direction: %v", err))
+		}
+	})
+
+	t.Run("Disconnected", func(t *testing.T) {
+		// Two separate triangles, with isolated node 6
+		g := mockUndirectedGraph(t, 7, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {3, 4, 1}, {4, 5, 1}, {5, 3, 1}})
+		var disconnected *DisconnectedError
+		if _, err := EulerianCircuit(g, nil, false); !errors.As(err, &disconnected) {
+			t.Fatalf("Circuit through separate components should fail with DisconnectedError")
+		}
+		if len(disconnected.Components) != 2 {
+			t.Fatalf(fmt.Sprintf("Components with edges should be %v but are %v", [][]int{{0, 1, 2}, {3, 4, 5}}, disconnected.Components))
+		}
+		checkInts(t, "Component", disconnected.Components[1], []int{3, 4, 5})
+
+		p, err := EulerianCircuit(mockGraph(t, 2, nil), nil, true)
+		if err != nil || len(p.Nodes) != 1 {
+			t.Fatalf(fmt.Sprintf("Graph without edges should have a circuit of one node but has %v: %v", p, err))
+		}
+	})
+
+	t.Run("Visitor", func(t *testing.T) {
+		edges := []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}}
+		g := mockGraph(t, 3, edges)
+		var examined []EdgeKey
+		v := &Visitor{ExamineEdge: func(e *structures.Edge) {
+			examined = append(examined, KeyOf(e))
+		}}
+		if _, err := EulerianCircuit(g, v, true); err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find Eulerian circuit: %v", err))
+		}
+		if len(examined) != len(edges) {
+			t.Fatalf(fmt.Sprintf("Each edge should be examined once but %v were", examined))
+		}
+	})
+}
+
+func TestChinesePostman(t *testing.T) {
+	log.Printf("Testing Chinese postman")
+
+	t.Run("Undirected", func(t *testing.T) {
+		// A square with diagonal 0-2 has odd nodes 0 and 2, and the diagonal
+		// is the shortest path between them
+		edges := []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}, {0, 2, 1.5}}
+		g := mockUndirectedGraph(t, 4, edges)
+		p, err := ChinesePostman(g, nil, false)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find postman walk: %v", err))
+		}
+		checkTrail(t, p.Circuit, append(edges, mockEdge{0, 2, 1.5}), false, 7)
+		if len(p.Duplicated) != 1 || undirectedKeyOf(p.Duplicated[0].From, p.Duplicated[0].To) != (EdgeKey{0, 2}) {
+			t.Fatalf(fmt.Sprintf("Diagonal should be duplicated but %v were", p.Duplicated))
+		}
+
+		// A heavy diagonal is cheaper to replace by the path around
+		edges[4].w = 3
+		g = mockUndirectedGraph(t, 4, edges)
+		p, _ = ChinesePostman(g, nil, false)
+		if p.Circuit.Cost != 9 || len(p.Duplicated) != 2 {
+			t.Fatalf(fmt.Sprintf("Postman walk should cost 9 duplicating 2 edges but is %v duplicating %v", p.Circuit, p.Duplicated))
+		}
+
+		// An Eulerian graph needs no duplicates
+		p, _ = ChinesePostman(mockUndirectedGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}}), nil, false)
+		if p.Circuit.Cost != 3 || len(p.Duplicated) != 0 {
+			t.Fatalf(fmt.Sprintf("Postman walk of a triangle should cost 3 but is %v", p.Circuit))
+		}
+	})
+
+	t.Run("Directed", func(t *testing.T) {
+		// Node 0 has an extra outgoing edge and node 2 an extra incoming edge,
+		// so the walk returns from 2 to 0 through 3
+		edges := []mockEdge{{0, 1, 1}, {1, 2, 1}, {0, 2, 1}, {2, 3, 2}, {3, 0, 2}}
+		g := mockGraph(t, 4, edges)
+		p, err := ChinesePostman(g, nil, true)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find postman walk: %v", err))
+		}
+		checkTrail(t, p.Circuit, append(edges, mockEdge{2, 3, 2}, mockEdge{3, 0, 2}), true, 11)
+		if len(p.Duplicated) != 2 {
+			t.Fatalf(fmt.Sprintf("Edges 2-3 and 3-0 should be duplicated but %v were", p.Duplicated))
+		}
+
+		var disconnected *DisconnectedError
+		g = mockGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}})
+		if _, err = ChinesePostman(g, nil, true); !errors.As(err, &disconnected) {
+			t.Fatalf("Postman walk of a path should fail with DisconnectedError")
+		}
+		if _, err = ChinesePostman(g, nil, false); err != nil {
+			t.Fatalf(fmt.Sprintf("Undirected postman walk of a path should walk it back: %v", err))
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var negative *NegativeWeightError
+		g := mockUndirectedGraph(t, 2, []mockEdge{{0, 1, -1}})
+		if _, err := ChinesePostman(g, nil, false); !errors.As(err, &negative) {
+			t.Fatalf("Postman walk with a negative weight should fail with NegativeWeightError")
+		}
+
+		var disconnected *DisconnectedError
+		g = mockUndirectedGraph(t, 4, []mockEdge{{0, 1, 1}, {2, 3, 1}})
+		if _, err := ChinesePostman(g, nil, false); !errors.As(err, &disconnected) {
+			t.Fatalf("Postman walk of separate components should fail with DisconnectedError")
+		}
+	})
+}
+
+// checkTrail checks that a trail walks each of the edges exactly once, in
+// either direction unless directed is set, and has the wanted cost
+func checkTrail(t *testing.T, p *Path, edges []mockEdge, directed bool, cost float64) {
+	t.Helper()
+	if len(p.Nodes) != len(edges)+1 || p.Cost != cost {
+		t.Fatalf(fmt.Sprintf("Trail through %v should cost %f but is %v", edges, cost, p))
+	}
+	left := make(map[EdgeKey]int)
+	for _, e := range edges {
+		if directed {
+			left[EdgeKey{e.from, e.to}]++
+		} else {
+			left[undirectedKeyOf(e.from, e.to)]++
+		}
+	}
+	for _, k := range p.Edges() {
+		if !directed {
+			k = undirectedKeyOf(k.From, k.To)
+		}
+		if left[k] == 0 {
+			t.Fatalf(fmt.Sprintf("Trail %v walks edge %v too often", p.Nodes, k))
+		}
+		left[k]--
+	}
+}
@@ -0,0 +1,386 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// flowEpsilon is the residual capacity below which an arc is treated as
+// saturated, so that rounding errors do not leave arcs open
+const flowEpsilon = 1e-9
+
+// SourceSinkError states that a flow was requested from a node to itself
+type SourceSinkError struct {
+	id  int
+	Err error
+}
+
+// Error serves the error message for SourceSinkError
+func (e *SourceSinkError) Error() string {
+	return fmt.Sprintf("Node %d cannot be both source and sink: %v", e.id, e.Err)
+}
+
+func (e *SourceSinkError) Unwrap() error { return e.Err }
+
+// Flow holds a maximum flow from a source node to a sink node, where the
+// capacity of each edge is its weight
+type Flow struct {
+	Source int `json:"source"`
+	Sink   int `json:"sink"`
+	// Value is the total flow leaving the source
+	Value float64 `json:"value"`
+	// EdgeFlow holds the flow along every edge that carries flow
+	EdgeFlow map[EdgeKey]float64 `json:"-"`
+	// SourceSide holds the IDs of the nodes on the source side of a minimum
+	// cut, which are the nodes reachable from the source in the residual graph
+	SourceSide []int `json:"sourceSide"`
+	// MinCut holds the edges from the source side to the sink side of the
+	// minimum cut. Every cut edge is saturated and their capacities sum to
+	// Value
+	MinCut []EdgeKey `json:"minCut"`
+}
+
+// arc is a directed arc of a residual network. Arcs are stored in pairs, so
+// the reverse of arc a is a^1
+type arc struct {
+	to       int
+	capacity float64
+	flow     float64
+	// cost is the cost per unit of flow along the arc. Reverse arcs refund
+	// the cost of their forward arc
+	cost float64
+	// edge is the graph edge of a forward arc, and nil for a reverse arc
+	edge *structures.Edge
+}
+
+// flowNetwork is the residual network of a graph with nodes indexed by their
+// position in the graph
+type flowNetwork struct {
+	g      *structures.Graph
+	v      *Visitor
+	index  map[int]int
+	adj    [][]int
+	arcs   []arc
+	source int
+	sink   int
+}
+
+// newFlowNetwork builds the residual network of g for a flow from source to
+// sink with no flow assigned
+func newFlowNetwork(g *structures.Graph, v *Visitor, source, sink int) (*flowNetwork, error) {
+	if source == sink {
+		return nil, &SourceSinkError{source, nil}
+	}
+	if _, err := g.GetNodeByID(source); err != nil {
+		return nil, err
+	}
+	if _, err := g.GetNodeByID(sink); err != nil {
+		return nil, err
+	}
+
+	f := &flowNetwork{
+		g:     g,
+		v:     v,
+		index: make(map[int]int, len(g.Nodes)),
+		adj:   make([][]int, len(g.Nodes)),
+	}
+	for i, n := range g.Nodes {
+		f.index[n.ID] = i
+	}
+	for i, n := range g.Nodes {
+		for _, e := range n.Edges {
+			if e.Weight < 0 {
+				return nil, &NegativeWeightError{KeyOf(e), e.Weight, nil}
+			}
+			j := f.index[e.Nodes[1].ID]
+			f.adj[i] = append(f.adj[i], len(f.arcs))
+			f.arcs = append(f.arcs, arc{to: j, capacity: e.Weight, cost: e.Cost, edge: e})
+			f.adj[j] = append(f.adj[j], len(f.arcs))
+			f.arcs = append(f.arcs, arc{to: i, cost: -e.Cost})
+		}
+	}
+	f.source = f.index[source]
+	f.sink = f.index[sink]
+	return f, nil
+}
+
+// residual returns the capacity left on arc a
+func (f *flowNetwork) residual(a int) float64 {
+	return f.arcs[a].capacity - f.arcs[a].flow
+}
+
+// id returns the node ID of the node at position i
+func (f *flowNetwork) id(i int) int {
+	return f.g.Nodes[i].ID
+}
+
+// push sends d units of flow along arc a
+func (f *flowNetwork) push(a int, d float64) {
+	f.arcs[a].flow += d
+	f.arcs[a^1].flow -= d
+	if f.arcs[a].edge == nil {
+		a ^= 1
+	}
+	f.v.assignFlow(f.arcs[a].edge, f.arcs[a].flow)
+}
+
+// augmentPath sends the largest flow allowed along a path of arcs and returns
+// the amount sent
+func (f *flowNetwork) augmentPath(path []int) float64 {
+	d := math.Inf(1)
+	for _, a := range path {
+		d = math.Min(d, f.residual(a))
+	}
+	for _, a := range path {
+		f.push(a, d)
+	}
+	return d
+}
+
+// levels returns the number of residual arcs on a shortest path from the
+// source to each node, or -1 for nodes that cannot be reached. If parent is
+// not nil, it is filled with the arc that reaches each node
+func (f *flowNetwork) levels(parent []int) []int {
+	level := make([]int, len(f.adj))
+	for i := range level {
+		level[i] = -1
+	}
+	level[f.source] = 0
+	f.v.discoverNode(f.id(f.source))
+
+	queue := []int{f.source}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		f.v.examineNode(f.id(u))
+		for _, a := range f.adj[u] {
+			w := f.arcs[a].to
+			if level[w] != -1 || f.residual(a) <= flowEpsilon {
+				continue
+			}
+			if e := f.arcs[a].edge; e != nil {
+				f.v.examineEdge(e)
+			}
+			level[w] = level[u] + 1
+			if parent != nil {
+				parent[w] = a
+			}
+			f.v.discoverNode(f.id(w))
+			queue = append(queue, w)
+		}
+		f.v.finishNode(f.id(u))
+	}
+	return level
+}
+
+// result collects the flow, value and minimum cut of the network
+func (f *flowNetwork) result() *Flow {
+	r := &Flow{
+		Source:   f.id(f.source),
+		Sink:     f.id(f.sink),
+		EdgeFlow: make(map[EdgeKey]float64),
+	}
+	for _, a := range f.adj[f.source] {
+		r.Value += f.arcs[a].flow
+	}
+
+	// The search for the cut is not part of the algorithm, so it is not
+	// visited
+	f.v = nil
+	reached := f.levels(nil)
+	for i, n := range f.g.Nodes {
+		if reached[i] == -1 {
+			continue
+		}
+		r.SourceSide = append(r.SourceSide, n.ID)
+	}
+	for i := range f.g.Nodes {
+		for _, a := range f.adj[i] {
+			c := f.arcs[a]
+			if c.edge == nil {
+				continue
+			}
+			if c.flow > flowEpsilon {
+				r.EdgeFlow[KeyOf(c.edge)] = c.flow
+			}
+			if reached[i] != -1 && reached[c.to] == -1 {
+				r.MinCut = append(r.MinCut, KeyOf(c.edge))
+			}
+		}
+	}
+	return r
+}
+
+// EdmondsKarp finds a maximum flow from source to sink in g, where the
+// capacity of each edge is its weight, by repeatedly sending flow along a
+// shortest path with capacity left. Capacities must not be negative
+func EdmondsKarp(g *structures.Graph, v *Visitor, source, sink int) (*Flow, error) {
+	f, err := newFlowNetwork(g, v, source, sink)
+	if err != nil {
+		return nil, fmt.Errorf("edmonds-karp: %w", err)
+	}
+
+	parent := make([]int, len(g.Nodes))
+	for {
+		if f.levels(parent)[f.sink] == -1 {
+			break
+		}
+
+		var path []int
+		for w := f.sink; w != f.source; w = f.arcs[parent[w]^1].to {
+			path = append(path, parent[w])
+		}
+		f.augmentPath(path)
+	}
+
+	return f.result(), nil
+}
+
+// Dinic finds a maximum flow from source to sink in g, where the capacity of
+// each edge is its weight. Each phase finds the shortest residual paths from
+// the source and sends a blocking flow along them, so that the next phase's
+// paths are longer. Capacities must not be negative
+func Dinic(g *structures.Graph, v *Visitor, source, sink int) (*Flow, error) {
+	f, err := newFlowNetwork(g, v, source, sink)
+	if err != nil {
+		return nil, fmt.Errorf("dinic: %w", err)
+	}
+
+	f.dinic()
+	return f.result(), nil
+}
+
+// dinic sends a maximum flow through the network with Dinic's algorithm
+func (f *flowNetwork) dinic() {
+	for {
+		level := f.levels(nil)
+		if level[f.sink] == -1 {
+			break
+		}
+
+		// next holds the position of the next arc to try from each node.
+		// Arcs that lead nowhere are skipped for the rest of the phase
+		next := make([]int, len(f.adj))
+		var path []int
+		u := f.source
+		for {
+			if u == f.sink {
+				f.augmentPath(path)
+				path = path[:0]
+				u = f.source
+				continue
+			}
+
+			advanced := false
+			for ; next[u] < len(f.adj[u]); next[u]++ {
+				a := f.adj[u][next[u]]
+				w := f.arcs[a].to
+				if f.residual(a) > flowEpsilon && level[w] == level[u]+1 {
+					path = append(path, a)
+					u = w
+					advanced = true
+					break
+				}
+			}
+			if advanced {
+				continue
+			}
+
+			// u is a dead end, so retreat along the path
+			if u == f.source {
+				break
+			}
+			a := path[len(path)-1]
+			path = path[:len(path)-1]
+			u = f.arcs[a^1].to
+			next[u]++
+		}
+	}
+}
+
+// PushRelabel finds a maximum flow from source to sink in g, where the
+// capacity of each edge is its weight. It floods the source's edges and then
+// pushes excess flow from overflowing nodes to lower neighbors, raising nodes
+// that cannot push until all excess reaches the sink or returns to the source.
+// Overflowing nodes are discharged in first in, first out order. Excess that
+// is left by rounding error and cannot move is dropped. Capacities must not be
+// negative
+func PushRelabel(g *structures.Graph, v *Visitor, source, sink int) (*Flow, error) {
+	f, err := newFlowNetwork(g, v, source, sink)
+	if err != nil {
+		return nil, fmt.Errorf("push-relabel: %w", err)
+	}
+
+	n := len(g.Nodes)
+	height := make([]int, n)
+	excess := make([]float64, n)
+	next := make([]int, n)
+	active := make([]bool, n)
+	var queue []int
+	activate := func(u int) {
+		if u != f.source && u != f.sink && !active[u] && excess[u] > flowEpsilon {
+			active[u] = true
+			queue = append(queue, u)
+			f.v.discoverNode(f.id(u))
+		}
+	}
+
+	height[f.source] = n
+	for _, a := range f.adj[f.source] {
+		if d := f.residual(a); d > 0 {
+			f.push(a, d)
+			excess[f.arcs[a].to] += d
+			excess[f.source] -= d
+			activate(f.arcs[a].to)
+		}
+	}
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		active[u] = false
+		f.v.examineNode(f.id(u))
+
+		for excess[u] > flowEpsilon {
+			if next[u] == len(f.adj[u]) {
+				// Relabel u to just above its lowest residual neighbor
+				lowest := math.MaxInt32
+				for _, a := range f.adj[u] {
+					if f.residual(a) > flowEpsilon && height[f.arcs[a].to] < lowest {
+						lowest = height[f.arcs[a].to]
+					}
+				}
+				if lowest >= 2*n-1 {
+					// Heights stay below 2n, since excess can always return
+					// the way it came, so excess without a residual arc or
+					// that would raise u further is rounding error
+					excess[u] = 0
+					break
+				}
+				height[u] = lowest + 1
+				next[u] = 0
+				continue
+			}
+
+			a := f.adj[u][next[u]]
+			w := f.arcs[a].to
+			if f.residual(a) <= flowEpsilon || height[u] != height[w]+1 {
+				next[u]++
+				continue
+			}
+			if e := f.arcs[a].edge; e != nil {
+				f.v.examineEdge(e)
+			}
+			d := math.Min(excess[u], f.residual(a))
+			f.push(a, d)
+			excess[u] -= d
+			excess[w] += d
+			activate(w)
+		}
+		f.v.finishNode(f.id(u))
+	}
+
+	return f.result(), nil
+}
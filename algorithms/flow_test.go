@@ -0,0 +1,142 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestMaxFlow(t *testing.T) {
+	log.Printf("Testing maximum flow")
+
+	// The flow network of Cormen et al. with a maximum flow of 23 from 0 to 5
+	edges := []mockEdge{
+		{0, 1, 16}, {0, 2, 13}, {1, 2, 10}, {2, 1, 4}, {1, 3, 12}, {3, 2, 9},
+		{2, 4, 14}, {4, 3, 7}, {3, 5, 20}, {4, 5, 4},
+	}
+
+	algs := map[string]func(*structures.Graph, *Visitor, int, int) (*Flow, error){
+		"Edmonds-Karp": EdmondsKarp,
+		"Dinic":        Dinic,
+		"Push-relabel": PushRelabel,
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			g := mockGraph(t, 7, edges)
+			assigned := make(map[EdgeKey]float64)
+			v := &Visitor{AssignFlow: func(e *structures.Edge, flow float64) {
+				assigned[KeyOf(e)] = flow
+			}}
+			f, err := alg(g, v, 0, 5)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find maximum flow: %v", err))
+			}
+			checkFlow(t, g, f, 23)
+			for k, flow := range f.EdgeFlow {
+				if assigned[k] != flow {
+					t.Fatalf(fmt.Sprintf("Visitor should see flow %f along %v but saw %f", flow, k, assigned[k]))
+				}
+			}
+
+			// Node 6 cannot be reached
+			f, err = alg(g, nil, 0, 6)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find maximum flow: %v", err))
+			}
+			checkFlow(t, g, f, 0)
+
+			var sourceSink *SourceSinkError
+			if _, err = alg(g, nil, 3, 3); !errors.As(err, &sourceSink) {
+				t.Fatalf("Flow from a node to itself should fail with SourceSinkError")
+			}
+			g = mockGraph(t, 2, []mockEdge{{0, 1, -1}})
+			var negative *NegativeWeightError
+			if _, err = alg(g, nil, 0, 1); !errors.As(err, &negative) {
+				t.Fatalf("Flow with a negative capacity should fail with NegativeWeightError")
+			}
+		})
+	}
+
+	t.Run("Antiparallel edges", func(t *testing.T) {
+		g := mockUndirectedGraph(t, 4, []mockEdge{{0, 1, 3}, {1, 2, 2}, {0, 2, 1}, {2, 3, 5}})
+		for name, alg := range algs {
+			f, err := alg(g, nil, 0, 3)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("%s could not find maximum flow: %v", name, err))
+			}
+			checkFlow(t, g, f, 3)
+		}
+	})
+
+	t.Run("Rounding error", func(t *testing.T) {
+		// The sink cannot be reached, so the flood from the source all
+		// returns to it, with 0.1 taking a detour through node 2. Taking 0.1
+		// from a large capacity and adding it back leaves a rounding error at
+		// node 1 with no residual arc to take it
+		g := structures.NewGraph(1e12)
+		for i := 0; i < 4; i++ {
+			g.SetNodeByID(i, float64(i), 0, 0, nil)
+		}
+		g.SetEdgeByNodeID(0, 1, 37400000000.1, "n", "n", false)
+		g.SetEdgeByNodeID(1, 2, 0.1, "n", "n", false)
+
+		done := make(chan *Flow)
+		go func() {
+			f, _ := PushRelabel(g, nil, 0, 3)
+			done <- f
+		}()
+		select {
+		case f := <-done:
+			// The flow is within the precision of the capacity
+			if math.Abs(f.Value) > 1e-5 {
+				t.Fatalf(fmt.Sprintf("Flow to an unreachable sink should be 0 but is %f", f.Value))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Push-relabel should finish despite rounding error")
+		}
+	})
+}
+
+// checkFlow checks that f is a valid flow of the wanted value whose minimum
+// cut has the same capacity
+func checkFlow(t *testing.T, g *structures.Graph, f *Flow, value float64) {
+	t.Helper()
+	if math.Abs(f.Value-value) > 1e-9 {
+		t.Fatalf(fmt.Sprintf("Flow should be %f but is %f", value, f.Value))
+	}
+
+	net := make(map[int]float64)
+	for k, flow := range f.EdgeFlow {
+		e, err := g.GetEdgeByNodeID(k.From, k.To)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Flow along %v should follow a graph edge", k))
+		}
+		if flow < 0 || flow > e.Weight+1e-9 {
+			t.Fatalf(fmt.Sprintf("Flow %f along %v should be within capacity %f", flow, k, e.Weight))
+		}
+		net[k.From] -= flow
+		net[k.To] += flow
+	}
+	for id, d := range net {
+		if id != f.Source && id != f.Sink && math.Abs(d) > 1e-9 {
+			t.Fatalf(fmt.Sprintf("Flow into node %d should equal flow out of it", id))
+		}
+	}
+
+	capacity := 0.0
+	for _, k := range f.MinCut {
+		e, _ := g.GetEdgeByNodeID(k.From, k.To)
+		if math.Abs(f.EdgeFlow[k]-e.Weight) > 1e-9 {
+			t.Fatalf(fmt.Sprintf("Cut edge %v should be saturated", k))
+		}
+		capacity += e.Weight
+	}
+	if math.Abs(capacity-value) > 1e-9 {
+		t.Fatalf(fmt.Sprintf("Minimum cut should have capacity %f but has %f", value, capacity))
+	}
+}
@@ -0,0 +1,37 @@
+package algorithms
+
+import (
+	"container/heap"
+)
+
+// pqItem is a node ID queued with a priority
+type pqItem struct {
+	id       int
+	priority float64
+}
+
+// priorityQueue is a min-heap of node IDs ordered by priority. Entries are not
+// updated in place, so a node may be queued several times and callers skip the
+// stale entries when they are popped
+type priorityQueue []pqItem
+
+func (q priorityQueue) Len() int            { return len(q) }
+func (q priorityQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q priorityQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *priorityQueue) Push(x interface{}) { *q = append(*q, x.(pqItem)) }
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// push queues node id with the specified priority
+func (q *priorityQueue) push(id int, priority float64) {
+	heap.Push(q, pqItem{id, priority})
+}
+
+// pop removes and returns the node with the lowest priority
+func (q *priorityQueue) pop() pqItem {
+	return heap.Pop(q).(pqItem)
+}
@@ -0,0 +1,323 @@
+package algorithms
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// Matcher holds optional predicates that decide whether a node or edge of a
+// pattern graph may map to a node or edge of a target graph. A nil predicate
+// accepts any pair
+type Matcher struct {
+	// Node reports whether pattern node p may map to target node t, e.g. by
+	// comparing their extra data with SameExtra
+	Node func(p, t *structures.Node) bool
+	// Edge reports whether pattern edge p may map to target edge t, e.g. by
+	// comparing their tags with SameTags
+	Edge func(p, t *structures.Edge) bool
+}
+
+func (m *Matcher) node(p, t *structures.Node) bool {
+	return m == nil || m.Node == nil || m.Node(p, t)
+}
+
+func (m *Matcher) edge(p, t *structures.Edge) bool {
+	return m == nil || m.Edge == nil || m.Edge(p, t)
+}
+
+// SameExtra reports whether nodes p and t hold equal extra data
+func SameExtra(p, t *structures.Node) bool {
+	if p.Extra == nil || t.Extra == nil {
+		return p.Extra == nil && t.Extra == nil
+	}
+	return reflect.DeepEqual(p.Extra.GetData(), t.Extra.GetData())
+}
+
+// SameTags reports whether edges p and t have the same tags on their near and
+// far nodes
+func SameTags(p, t *structures.Edge) bool {
+	return p.Nodes[0].Tag == t.Nodes[0].Tag && p.Nodes[1].Tag == t.Nodes[1].Tag
+}
+
+// Isomorphisms finds the isomorphisms from g1 to g2, the mappings of the node
+// IDs of g1 onto those of g2 under which an edge joins two nodes of g1 exactly
+// when an edge joins their images in the same direction, with the algorithm of
+// VF2. Mapped nodes and edges must also satisfy the predicates of m, which may
+// be nil. The search stops after limit mappings unless limit is 0
+func Isomorphisms(g1, g2 *structures.Graph, m *Matcher, limit int) []map[int]int {
+	if len(g1.Nodes) != len(g2.Nodes) {
+		return nil
+	}
+	return newVF2(g1, g2, m, true, limit).run()
+}
+
+// SubgraphIsomorphisms finds the occurrences of pattern in target, the
+// one-to-one mappings of the node IDs of pattern into those of target under
+// which each edge of pattern has an image joining the images of its nodes in
+// the same direction, with the algorithm of VF2. If induced is set, the images
+// of two nodes of pattern are also only joined where the nodes are. Mapped
+// nodes and edges must satisfy the predicates of m, which may be nil. The
+// search stops after limit mappings unless limit is 0
+func SubgraphIsomorphisms(pattern, target *structures.Graph, m *Matcher, induced bool, limit int) []map[int]int {
+	if len(pattern.Nodes) > len(target.Nodes) {
+		return nil
+	}
+	return newVF2(pattern, target, m, induced, limit).run()
+}
+
+// vf2Graph holds the edges of a graph by node position
+type vf2Graph struct {
+	nodes []*structures.Node
+	// out and in hold the edges leaving and entering each node by the position
+	// of the node at their other end, and outList and inList hold those
+	// positions in increasing order. Self loops are held in loop instead
+	out, in         []map[int]*structures.Edge
+	outList, inList [][]int
+	loop            []*structures.Edge
+}
+
+func newVF2Graph(g *structures.Graph) *vf2Graph {
+	n := len(g.Nodes)
+	vg := &vf2Graph{
+		nodes:   g.Nodes,
+		out:     make([]map[int]*structures.Edge, n),
+		in:      make([]map[int]*structures.Edge, n),
+		outList: make([][]int, n),
+		inList:  make([][]int, n),
+		loop:    make([]*structures.Edge, n),
+	}
+	position := make(map[int]int, n)
+	for i, node := range g.Nodes {
+		position[node.ID] = i
+		vg.out[i] = make(map[int]*structures.Edge)
+		vg.in[i] = make(map[int]*structures.Edge)
+	}
+	for i, node := range g.Nodes {
+		for _, e := range node.Edges {
+			j := position[e.Nodes[1].ID]
+			if j == i {
+				vg.loop[i] = e
+				continue
+			}
+			vg.out[i][j] = e
+			vg.in[j][i] = e
+		}
+	}
+	for i := range g.Nodes {
+		for j := range vg.out[i] {
+			vg.outList[i] = append(vg.outList[i], j)
+		}
+		for j := range vg.in[i] {
+			vg.inList[i] = append(vg.inList[i], j)
+		}
+		sort.Ints(vg.outList[i])
+		sort.Ints(vg.inList[i])
+	}
+	return vg
+}
+
+func (vg *vf2Graph) degree(i int) int {
+	return len(vg.out[i]) + len(vg.in[i])
+}
+
+// vf2 is the state of a search for mappings of the nodes of pattern graph p
+// into target graph t
+type vf2 struct {
+	p, t    *vf2Graph
+	match   *Matcher
+	induced bool
+	limit   int
+
+	// order holds the pattern positions in matching order. anchor holds for
+	// each pattern position an earlier neighbor in that order, or -1, and
+	// anchorOut whether the edge leaves the anchor, so that candidates are
+	// drawn from the neighbors of the image of the anchor
+	order     []int
+	anchor    []int
+	anchorOut []bool
+
+	// core maps pattern positions to target positions and inverse maps back,
+	// with -1 for unmapped positions
+	core, inverse []int
+	mappings      []map[int]int
+}
+
+func newVF2(pattern, target *structures.Graph, m *Matcher, induced bool, limit int) *vf2 {
+	s := &vf2{
+		p:       newVF2Graph(pattern),
+		t:       newVF2Graph(target),
+		match:   m,
+		induced: induced,
+		limit:   limit,
+		core:    make([]int, len(pattern.Nodes)),
+		inverse: make([]int, len(target.Nodes)),
+	}
+	for i := range s.core {
+		s.core[i] = -1
+	}
+	for i := range s.inverse {
+		s.inverse[i] = -1
+	}
+	s.orderPattern()
+	return s
+}
+
+// orderPattern orders the pattern nodes as VF2++ does, so that constraints
+// come into play early: each next node has the most edges to the nodes
+// already ordered, with ties going to the node of greatest degree
+func (s *vf2) orderPattern() {
+	n := len(s.p.nodes)
+	ordered := make([]bool, n)
+	edges := make([]int, n)
+	for len(s.order) < n {
+		next := -1
+		for u := 0; u < n; u++ {
+			if ordered[u] {
+				continue
+			}
+			if next == -1 || edges[u] > edges[next] ||
+				(edges[u] == edges[next] && s.p.degree(u) > s.p.degree(next)) {
+				next = u
+			}
+		}
+		s.order = append(s.order, next)
+		ordered[next] = true
+		for _, w := range s.p.outList[next] {
+			edges[w]++
+		}
+		for _, w := range s.p.inList[next] {
+			edges[w]++
+		}
+	}
+
+	s.anchor = make([]int, n)
+	s.anchorOut = make([]bool, n)
+	done := make([]bool, n)
+	for _, u := range s.order {
+		s.anchor[u] = -1
+		for _, w := range s.p.inList[u] {
+			if done[w] {
+				s.anchor[u], s.anchorOut[u] = w, true
+				break
+			}
+		}
+		if s.anchor[u] == -1 {
+			for _, w := range s.p.outList[u] {
+				if done[w] {
+					s.anchor[u] = w
+					break
+				}
+			}
+		}
+		done[u] = true
+	}
+}
+
+func (s *vf2) run() []map[int]int {
+	s.extend(0)
+	return s.mappings
+}
+
+func (s *vf2) done() bool {
+	return s.limit > 0 && len(s.mappings) >= s.limit
+}
+
+// extend maps the pattern nodes from the depth in matching order onward in
+// every feasible way
+func (s *vf2) extend(depth int) {
+	if depth == len(s.order) {
+		mapping := make(map[int]int, len(s.core))
+		for u, v := range s.core {
+			mapping[s.p.nodes[u].ID] = s.t.nodes[v].ID
+		}
+		s.mappings = append(s.mappings, mapping)
+		return
+	}
+
+	u := s.order[depth]
+	var candidates []int
+	if a := s.anchor[u]; a == -1 {
+		candidates = make([]int, len(s.t.nodes))
+		for v := range candidates {
+			candidates[v] = v
+		}
+	} else if s.anchorOut[u] {
+		candidates = s.t.outList[s.core[a]]
+	} else {
+		candidates = s.t.inList[s.core[a]]
+	}
+	for _, v := range candidates {
+		if !s.feasible(u, v) {
+			continue
+		}
+		s.core[u], s.inverse[v] = v, u
+		s.extend(depth + 1)
+		s.core[u], s.inverse[v] = -1, -1
+		if s.done() {
+			return
+		}
+	}
+}
+
+// feasible reports whether pattern node u may map to target node v given the
+// nodes mapped so far
+func (s *vf2) feasible(u, v int) bool {
+	p, t := s.p, s.t
+	if s.inverse[v] != -1 || len(p.out[u]) > len(t.out[v]) || len(p.in[u]) > len(t.in[v]) ||
+		!s.match.node(p.nodes[u], t.nodes[v]) {
+		return false
+	}
+	if p.loop[u] != nil && (t.loop[v] == nil || !s.match.edge(p.loop[u], t.loop[v])) ||
+		s.induced && p.loop[u] == nil && t.loop[v] != nil {
+		return false
+	}
+	if !s.mapsEdges(p.out[u], t.out[v]) || !s.mapsEdges(p.in[u], t.in[v]) {
+		return false
+	}
+	if s.induced && (!s.coversEdges(t.out[v], p.out[u]) || !s.coversEdges(t.in[v], p.in[u])) {
+		return false
+	}
+
+	// Each unmapped neighbor of u needs its own unmapped neighbor of v
+	return unmapped(p.outList[u], s.core) <= unmapped(t.outList[v], s.inverse) &&
+		unmapped(p.inList[u], s.core) <= unmapped(t.inList[v], s.inverse)
+}
+
+// mapsEdges reports whether each of the pattern edges pe to a mapped node has
+// a matching target edge te to its image
+func (s *vf2) mapsEdges(pe, te map[int]*structures.Edge) bool {
+	for w, e := range pe {
+		if x := s.core[w]; x != -1 {
+			if image, ok := te[x]; !ok || !s.match.edge(e, image) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// coversEdges reports whether each of the target edges te to a mapped node has
+// a pattern edge pe to its preimage
+func (s *vf2) coversEdges(te, pe map[int]*structures.Edge) bool {
+	for x := range te {
+		if w := s.inverse[x]; w != -1 {
+			if _, ok := pe[w]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// unmapped counts the positions that are not mapped by mapping
+func unmapped(positions, mapping []int) int {
+	count := 0
+	for _, i := range positions {
+		if mapping[i] == -1 {
+			count++
+		}
+	}
+	return count
+}
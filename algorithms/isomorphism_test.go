@@ -0,0 +1,251 @@
+package algorithms
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestIsomorphism(t *testing.T) {
+	log.Printf("Testing isomorphism")
+
+	cycle := []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}}
+	triangle := []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}}
+	complete := []mockEdge{{0, 1, 1}, {0, 2, 1}, {0, 3, 1}, {1, 2, 1}, {1, 3, 1}, {2, 3, 1}}
+
+	t.Run("Isomorphisms", func(t *testing.T) {
+		// A directed 4-cycle maps onto itself by its 4 rotations, and an
+		// undirected one by its reflections too
+		g := mockGraph(t, 4, cycle)
+		checkMappings(t, g, g, Isomorphisms(g, g, nil, 0), true, 4)
+		g = mockUndirectedGraph(t, 4, cycle)
+		checkMappings(t, g, g, Isomorphisms(g, g, nil, 0), true, 8)
+
+		// The same cycle numbered 0 2 1 3
+		h := mockUndirectedGraph(t, 4, []mockEdge{{0, 2, 1}, {2, 1, 1}, {1, 3, 1}, {3, 0, 1}})
+		checkMappings(t, g, h, Isomorphisms(g, h, nil, 0), true, 8)
+
+		// A path and a star have the same numbers of nodes and edges
+		path := mockUndirectedGraph(t, 4, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}})
+		star := mockUndirectedGraph(t, 4, []mockEdge{{0, 1, 1}, {0, 2, 1}, {0, 3, 1}})
+		checkMappings(t, path, star, Isomorphisms(path, star, nil, 0), true, 0)
+		checkMappings(t, g, path, Isomorphisms(g, path, nil, 0), true, 0)
+		small := mockGraph(t, 3, nil)
+		checkMappings(t, g, small, Isomorphisms(g, small, nil, 0), true, 0)
+
+		empty := mockGraph(t, 0, nil)
+		checkMappings(t, empty, empty, Isomorphisms(empty, empty, nil, 0), true, 1)
+	})
+
+	t.Run("Subgraphs", func(t *testing.T) {
+		k4 := mockUndirectedGraph(t, 4, complete)
+		c4 := mockUndirectedGraph(t, 4, cycle)
+		tri := mockUndirectedGraph(t, 3, triangle)
+		path := mockUndirectedGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}})
+
+		checkMappings(t, tri, k4, SubgraphIsomorphisms(tri, k4, nil, true, 0), true, 24)
+		checkMappings(t, tri, c4, SubgraphIsomorphisms(tri, c4, nil, false, 0), false, 0)
+
+		// Each node of a 4-cycle is the middle of a path in two directions,
+		// and the ends of those paths are not joined. In a complete graph
+		// they always are
+		checkMappings(t, path, c4, SubgraphIsomorphisms(path, c4, nil, true, 0), true, 8)
+		checkMappings(t, path, k4, SubgraphIsomorphisms(path, k4, nil, true, 0), true, 0)
+		checkMappings(t, path, k4, SubgraphIsomorphisms(path, k4, nil, false, 0), false, 24)
+
+		// A directed edge matches in its own direction only
+		edge := mockGraph(t, 2, []mockEdge{{0, 1, 1}})
+		g := mockGraph(t, 3, []mockEdge{{0, 1, 1}, {2, 1, 1}})
+		mappings := SubgraphIsomorphisms(edge, g, nil, false, 0)
+		checkMappings(t, edge, g, mappings, false, 2)
+		for _, m := range mappings {
+			if m[1] != 1 {
+				t.Fatalf(fmt.Sprintf("Edge should map onto an edge into node 1 but maps as %v", m))
+			}
+		}
+
+		// Self loops map onto self loops
+		loop := mockGraph(t, 1, []mockEdge{{0, 0, 1}})
+		g = mockGraph(t, 3, []mockEdge{{0, 1, 1}, {2, 2, 1}})
+		checkMappings(t, loop, g, SubgraphIsomorphisms(loop, g, nil, false, 0), false, 1)
+		node := mockGraph(t, 1, nil)
+		checkMappings(t, node, g, SubgraphIsomorphisms(node, g, nil, true, 0), true, 2)
+
+		checkMappings(t, k4, tri, SubgraphIsomorphisms(k4, tri, nil, false, 0), false, 0)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		k4 := mockUndirectedGraph(t, 4, complete)
+		for _, limit := range []int{1, 5, 24} {
+			mappings := SubgraphIsomorphisms(mockUndirectedGraph(t, 3, triangle), k4, nil, true, limit)
+			if len(mappings) != limit {
+				t.Fatalf(fmt.Sprintf("Search should stop after %d mappings but found %d", limit, len(mappings)))
+			}
+		}
+		if mappings := Isomorphisms(k4, k4, nil, 100); len(mappings) != 24 {
+			t.Fatalf(fmt.Sprintf("Complete graph should have 24 automorphisms but has %d", len(mappings)))
+		}
+	})
+
+	t.Run("Matchers", func(t *testing.T) {
+		color := func(g *structures.Graph, id int, c string) {
+			n, _ := g.GetNodeByID(id)
+			n.Extra = structures.ColorData{Color: c}
+		}
+
+		// A red node joined to a blue node occurs once in a path colored
+		// green, red, blue
+		pattern := mockUndirectedGraph(t, 2, []mockEdge{{0, 1, 1}})
+		color(pattern, 0, "red")
+		color(pattern, 1, "blue")
+		target := mockUndirectedGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}})
+		color(target, 0, "green")
+		color(target, 1, "red")
+		color(target, 2, "blue")
+		m := &Matcher{Node: SameExtra}
+		mappings := SubgraphIsomorphisms(pattern, target, m, true, 0)
+		checkMappings(t, pattern, target, mappings, true, 1)
+		if mappings[0][0] != 1 || mappings[0][1] != 2 {
+			t.Fatalf(fmt.Sprintf("Red and blue nodes should map onto nodes 1 and 2 but map as %v", mappings[0]))
+		}
+
+		// Edge tags tell the parent of a node from its child
+		pattern = structures.NewGraph(1000)
+		target = structures.NewGraph(1000)
+		for id := 0; id < 3; id++ {
+			pattern.SetNodeByID(id, 0, 0, 0, nil)
+			target.SetNodeByID(id, 0, 0, 0, nil)
+		}
+		pattern.SetEdgeByNodeID(0, 1, 1, "p", "c", false)
+		target.SetEdgeByNodeID(0, 1, 1, "c", "p", false)
+		target.SetEdgeByNodeID(1, 2, 1, "p", "c", false)
+		m = &Matcher{Edge: SameTags}
+		mappings = SubgraphIsomorphisms(pattern, target, m, false, 0)
+		if len(mappings) != 1 || mappings[0][0] != 1 || mappings[0][1] != 2 {
+			t.Fatalf(fmt.Sprintf("Tagged edge should map onto the edge from 1 to 2 but maps as %v", mappings))
+		}
+
+		// Retagging an edge keeps the graph isomorphic only without the tags
+		retagged := mockGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}})
+		if len(Isomorphisms(target, retagged, nil, 0)) != 1 || len(Isomorphisms(target, retagged, m, 0)) != 0 {
+			t.Fatalf("Paths should only be isomorphic when tags are ignored")
+		}
+	})
+
+	t.Run("Random graphs", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		randomEdges := func(n int, p int) []mockEdge {
+			var edges []mockEdge
+			for a := 0; a < n; a++ {
+				for b := 0; b < n; b++ {
+					if r.Intn(p) == 0 {
+						edges = append(edges, mockEdge{a, b, 1})
+					}
+				}
+			}
+			return edges
+		}
+		for i := 0; i < 100; i++ {
+			n1, n2 := 1+r.Intn(4), 1+r.Intn(6)
+			pattern := mockGraph(t, n1, randomEdges(n1, 3))
+			target := mockGraph(t, n2, randomEdges(n2, 2))
+			for _, induced := range []bool{false, true} {
+				mappings := SubgraphIsomorphisms(pattern, target, nil, induced, 0)
+				checkMappings(t, pattern, target, mappings, induced, bruteForceMappings(pattern, target, induced))
+			}
+
+			// A random renumbering of a graph is isomorphic to it
+			edges := randomEdges(n2, 2)
+			perm := r.Perm(n2)
+			renumbered := make([]mockEdge, len(edges))
+			for k, e := range edges {
+				renumbered[k] = mockEdge{perm[e.from], perm[e.to], 1}
+			}
+			g, h := mockGraph(t, n2, edges), mockGraph(t, n2, renumbered)
+			mappings := Isomorphisms(g, h, nil, 0)
+			checkMappings(t, g, h, mappings, true, bruteForceMappings(g, h, true))
+			if len(mappings) == 0 {
+				t.Fatalf(fmt.Sprintf("Renumbered graph %v should be isomorphic", edges))
+			}
+		}
+	})
+}
+
+// checkMappings checks that there are the wanted number of distinct mappings
+// of pattern into target, each of which maps the edges of pattern onto edges
+// of target and, if induced is set, only those edges
+func checkMappings(t *testing.T, pattern, target *structures.Graph, mappings []map[int]int, induced bool, count int) {
+	t.Helper()
+	if len(mappings) != count {
+		t.Fatalf(fmt.Sprintf("There should be %d mappings but there are %d: %v", count, len(mappings), mappings))
+	}
+	seen := make(map[string]bool)
+	for _, m := range mappings {
+		if !isMapping(pattern, target, m, induced) {
+			t.Fatalf(fmt.Sprintf("%v does not map the edges of the pattern", m))
+		}
+		if key := fmt.Sprint(m); seen[key] {
+			t.Fatalf(fmt.Sprintf("Mapping %v is found twice", m))
+		} else {
+			seen[key] = true
+		}
+	}
+}
+
+// isMapping reports whether m maps the nodes of pattern one to one into target
+// so that every edge has an image and, if induced is set, so that the images
+// of the nodes are only joined where the nodes are
+func isMapping(pattern, target *structures.Graph, m map[int]int, induced bool) bool {
+	images := make(map[int]bool)
+	for _, n := range pattern.Nodes {
+		image, ok := m[n.ID]
+		if _, err := target.GetNodeByID(image); !ok || err != nil || images[image] {
+			return false
+		}
+		images[image] = true
+	}
+	if len(m) != len(pattern.Nodes) {
+		return false
+	}
+	for _, a := range pattern.Nodes {
+		for _, b := range pattern.Nodes {
+			_, errP := pattern.GetEdgeByNodeID(a.ID, b.ID)
+			_, errT := target.GetEdgeByNodeID(m[a.ID], m[b.ID])
+			if errP == nil && errT != nil || induced && errP != nil && errT == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// bruteForceMappings counts the mappings of pattern into target by checking
+// every one to one assignment of target nodes to the pattern nodes
+func bruteForceMappings(pattern, target *structures.Graph, induced bool) int {
+	count := 0
+	m := make(map[int]int)
+	used := make(map[int]bool)
+	var assign func(i int)
+	assign = func(i int) {
+		if i == len(pattern.Nodes) {
+			if isMapping(pattern, target, m, induced) {
+				count++
+			}
+			return
+		}
+		for _, n := range target.Nodes {
+			if !used[n.ID] {
+				used[n.ID] = true
+				m[pattern.Nodes[i].ID] = n.ID
+				assign(i + 1)
+				delete(m, pattern.Nodes[i].ID)
+				used[n.ID] = false
+			}
+		}
+	}
+	assign(0)
+	return count
+}
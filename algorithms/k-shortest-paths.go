@@ -0,0 +1,337 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// endNodes returns the source and target nodes of g with the requested IDs
+func endNodes(g *structures.Graph, source, target int) (*structures.Node, *structures.Node, error) {
+	s, err := g.GetNodeByID(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	t, err := g.GetNodeByID(target)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, t, nil
+}
+
+// pathCost returns the total weight of the edges along the node IDs of a path
+// through g
+func pathCost(g *structures.Graph, nodes []int) float64 {
+	cost := 0.0
+	for i := 1; i < len(nodes); i++ {
+		e, _ := g.GetEdgeByNodeID(nodes[i-1], nodes[i])
+		cost += e.Weight
+	}
+	return cost
+}
+
+// avoidingPath finds the shortest path from source to target that passes
+// through none of the nodes and takes none of the edges
+func avoidingPath(g *structures.Graph, source, target *structures.Node, nodes map[int]bool, edges map[EdgeKey]bool) (*Path, error) {
+	w := func(e *structures.Edge) float64 {
+		if nodes[e.Nodes[1].ID] || edges[KeyOf(e)] {
+			return math.Inf(1)
+		}
+		return e.Weight
+	}
+	sp, err := bestFirstSearch(g, nil, source, target, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	p, err := sp.PathTo(target.ID)
+	if err == nil && math.IsInf(p.Cost, 1) {
+		err = &NoPathError{source.ID, target.ID, nil}
+	}
+	return p, err
+}
+
+// KShortestPaths finds up to k of the shortest loopless paths from the source
+// node to the target node in order of cost, with the algorithm of Yen. Each
+// path after the first leaves an earlier path at some node, and the cheapest
+// such detour that avoids the nodes before it and the edges that the earlier
+// paths take from it is the next path. Paths of equal cost are ordered by
+// number of nodes. Edge weights must not be negative
+func KShortestPaths(g *structures.Graph, source, target, k int) ([]*Path, error) {
+	s, t, err := endNodes(g, source, target)
+	if err != nil {
+		return nil, fmt.Errorf("k shortest paths: %w", err)
+	}
+	if err := checkWeights(g); err != nil {
+		return nil, fmt.Errorf("k shortest paths: %w", err)
+	}
+	if k < 1 {
+		return nil, nil
+	}
+
+	first, err := avoidingPath(g, s, t, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("k shortest paths: %w", err)
+	}
+	paths := []*Path{first}
+	seen := map[string]bool{fmt.Sprint(first.Nodes): true}
+	var candidates []*Path
+	for len(paths) < k {
+		prev := paths[len(paths)-1]
+		rootCost := 0.0
+		for i := 0; i+1 < len(prev.Nodes); i++ {
+			root := prev.Nodes[:i+1]
+			nodes := make(map[int]bool)
+			for _, id := range root[:i] {
+				nodes[id] = true
+			}
+			edges := make(map[EdgeKey]bool)
+			for _, p := range paths {
+				if len(p.Nodes) > i+1 && equalInts(p.Nodes[:i+1], root) {
+					edges[EdgeKey{p.Nodes[i], p.Nodes[i+1]}] = true
+				}
+			}
+
+			spurNode, _ := g.GetNodeByID(prev.Nodes[i])
+			if spur, err := avoidingPath(g, spurNode, t, nodes, edges); err == nil {
+				p := &Path{append(root[:i:i], spur.Nodes...), rootCost + spur.Cost}
+				if key := fmt.Sprint(p.Nodes); !seen[key] {
+					seen[key] = true
+					candidates = append(candidates, p)
+				}
+			}
+			e, _ := g.GetEdgeByNodeID(prev.Nodes[i], prev.Nodes[i+1])
+			rootCost += e.Weight
+		}
+		if len(candidates) == 0 {
+			break
+		}
+
+		best := 0
+		for j, p := range candidates {
+			if p.Cost < candidates[best].Cost ||
+				(p.Cost == candidates[best].Cost && len(p.Nodes) < len(candidates[best].Nodes)) {
+				best = j
+			}
+		}
+		paths = append(paths, candidates[best])
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+	return paths, nil
+}
+
+// equalInts reports whether a and b hold the same values in the same order
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sidetrack is an edge off the shortest path tree to the target, along with
+// how much more a walk costs for taking it
+type sidetrack struct {
+	e     *structures.Edge
+	delta float64
+}
+
+// walkState is a walk by its last sidetrack, list[index], and the state of the
+// walk before that sidetrack, parent. The root state without a parent is the
+// shortest path
+type walkState struct {
+	parent int
+	list   []sidetrack
+	index  int
+	cost   float64
+}
+
+// distancesTo returns the cost of the shortest path from each node of g that
+// reaches target, along with the first edge of that path
+func distancesTo(g *structures.Graph, target *structures.Node) (map[int]float64, map[int]*structures.Edge) {
+	dist := map[int]float64{target.ID: 0}
+	next := make(map[int]*structures.Edge)
+	settled := make(map[int]bool)
+	q := &priorityQueue{}
+	q.push(target.ID, 0)
+	for q.Len() > 0 {
+		id := q.pop().id
+		if settled[id] {
+			continue
+		}
+		settled[id] = true
+		n, _ := g.GetNodeByID(id)
+		for _, e := range g.InEdges(n) {
+			m := e.Nodes[0].ID
+			if settled[m] {
+				continue
+			}
+			d := dist[id] + e.Weight
+			if old, ok := dist[m]; !ok || d < old {
+				dist[m] = d
+				next[m] = e
+				q.push(m, d)
+			}
+		}
+	}
+	return dist, next
+}
+
+// KShortestWalks finds up to k of the shortest walks from the source node to
+// the target node in order of cost, in the manner of Eppstein. Walks may
+// repeat nodes and edges, and may pass through the target before ending
+// there. Every walk follows the shortest path tree to the target apart from a
+// sequence of sidetrack edges, each costing a fixed amount more than the tree
+// path from its near node. Each node keeps the sidetracks from it and the rest
+// of its tree path sorted by that amount, so each walk leads to at most two
+// next cheapest walks: one taking the next sidetrack in place of its last and
+// one adding a sidetrack after its last. Edge weights must not be negative
+func KShortestWalks(g *structures.Graph, source, target, k int) ([]*Path, error) {
+	s, t, err := endNodes(g, source, target)
+	if err != nil {
+		return nil, fmt.Errorf("k shortest walks: %w", err)
+	}
+	if err := checkWeights(g); err != nil {
+		return nil, fmt.Errorf("k shortest walks: %w", err)
+	}
+	dist, next := distancesTo(g, t)
+	if _, ok := dist[s.ID]; !ok {
+		return nil, fmt.Errorf("k shortest walks: %w", &NoPathError{source, target, nil})
+	}
+
+	// sidetracks returns the sidetracks from the node with the specified ID
+	// and the nodes on its tree path, sorted by the added cost
+	lists := make(map[int][]sidetrack)
+	sidetracks := func(id int) []sidetrack {
+		var path []int
+		for x := id; ; x = next[x].Nodes[1].ID {
+			if _, ok := lists[x]; ok {
+				break
+			}
+			path = append(path, x)
+			if x == t.ID {
+				break
+			}
+		}
+		for i := len(path) - 1; i >= 0; i-- {
+			x := path[i]
+			n, _ := g.GetNodeByID(x)
+			var own []sidetrack
+			for _, e := range n.Edges {
+				d, ok := dist[e.Nodes[1].ID]
+				if !ok || e == next[x] {
+					continue
+				}
+				own = append(own, sidetrack{e, math.Max(0, e.Weight+d-dist[x])})
+			}
+			if x != t.ID {
+				own = append(own, lists[next[x].Nodes[1].ID]...)
+			}
+			sort.SliceStable(own, func(a, b int) bool {
+				return own[a].delta < own[b].delta
+			})
+			lists[x] = own
+		}
+		return lists[id]
+	}
+
+	// walk follows the tree path from the source through each sidetrack of
+	// the state in turn
+	var states []walkState
+	walk := func(i int) *Path {
+		var taken []*structures.Edge
+		for ; states[i].parent != -1; i = states[i].parent {
+			taken = append(taken, states[i].list[states[i].index].e)
+		}
+		nodes := []int{s.ID}
+		follow := func(to int) {
+			for x := nodes[len(nodes)-1]; x != to; {
+				x = next[x].Nodes[1].ID
+				nodes = append(nodes, x)
+			}
+		}
+		for j := len(taken) - 1; j >= 0; j-- {
+			follow(taken[j].Nodes[0].ID)
+			nodes = append(nodes, taken[j].Nodes[1].ID)
+		}
+		follow(t.ID)
+		return &Path{nodes, pathCost(g, nodes)}
+	}
+
+	q := &priorityQueue{}
+	push := func(state walkState) {
+		states = append(states, state)
+		q.push(len(states)-1, state.cost)
+	}
+	push(walkState{parent: -1, cost: dist[s.ID]})
+	var walks []*Path
+	for q.Len() > 0 && len(walks) < k {
+		i := q.pop().id
+		st := states[i]
+		walks = append(walks, walk(i))
+
+		head := s.ID
+		if st.parent != -1 {
+			last := st.list[st.index]
+			head = last.e.Nodes[1].ID
+			if st.index+1 < len(st.list) {
+				push(walkState{st.parent, st.list, st.index + 1, st.cost - last.delta + st.list[st.index+1].delta})
+			}
+		}
+		if list := sidetracks(head); len(list) > 0 {
+			push(walkState{i, list, 0, st.cost + list[0].delta})
+		}
+	}
+	return walks, nil
+}
+
+// SimplePaths finds every path from the source node to the target node that
+// repeats no node, in order of cost, by depth-first search. Paths of more than
+// maxEdges edges or costing more than maxCost are left out, where a limit that
+// is not positive is ignored. Negative weights are allowed, though they keep
+// the cost limit from pruning the search
+func SimplePaths(g *structures.Graph, source, target, maxEdges int, maxCost float64) ([]*Path, error) {
+	s, t, err := endNodes(g, source, target)
+	if err != nil {
+		return nil, fmt.Errorf("simple paths: %w", err)
+	}
+	prune := maxCost > 0 && checkWeights(g) == nil
+
+	var paths []*Path
+	nodes := []int{s.ID}
+	onPath := map[int]bool{s.ID: true}
+	var extend func(n *structures.Node, cost float64)
+	extend = func(n *structures.Node, cost float64) {
+		if n.ID == t.ID {
+			if maxCost <= 0 || cost <= maxCost {
+				paths = append(paths, &Path{append([]int{}, nodes...), cost})
+			}
+			return
+		}
+		if maxEdges > 0 && len(nodes)-1 >= maxEdges {
+			return
+		}
+		for _, e := range n.Edges {
+			m := e.Nodes[1].Node
+			if onPath[m.ID] || (prune && cost+e.Weight > maxCost) {
+				continue
+			}
+			onPath[m.ID] = true
+			nodes = append(nodes, m.ID)
+			extend(m, cost+e.Weight)
+			nodes = nodes[:len(nodes)-1]
+			onPath[m.ID] = false
+		}
+	}
+	extend(s, 0)
+
+	sort.SliceStable(paths, func(a, b int) bool {
+		return paths[a].Cost < paths[b].Cost
+	})
+	return paths, nil
+}
@@ -0,0 +1,247 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestKShortestPaths(t *testing.T) {
+	log.Printf("Testing k shortest paths")
+
+	// The example of Yen's algorithm with nodes C D E F G H numbered 0 to 5
+	yen := []mockEdge{
+		{0, 1, 3}, {0, 2, 2}, {1, 3, 4}, {2, 1, 1}, {2, 3, 2},
+		{2, 4, 3}, {3, 4, 2}, {3, 5, 1}, {4, 5, 2},
+	}
+
+	t.Run("Yen", func(t *testing.T) {
+		g := mockGraph(t, 6, yen)
+		paths, err := KShortestPaths(g, 0, 5, 3)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find k shortest paths: %v", err))
+		}
+		checkPaths(t, g, paths, 0, 5, true, []float64{5, 7, 8})
+		checkInts(t, "Shortest path", paths[0].Nodes, []int{0, 2, 3, 5})
+		checkInts(t, "Second shortest path", paths[1].Nodes, []int{0, 2, 4, 5})
+		checkInts(t, "Third shortest path", paths[2].Nodes, []int{0, 1, 3, 5})
+
+		// Only 7 loopless paths exist
+		paths, _ = KShortestPaths(g, 0, 5, 100)
+		checkPaths(t, g, paths, 0, 5, true, []float64{5, 7, 8, 8, 8, 11, 11})
+
+		paths, _ = KShortestPaths(g, 0, 0, 3)
+		checkPaths(t, g, paths, 0, 0, true, []float64{0})
+	})
+
+	t.Run("Eppstein", func(t *testing.T) {
+		// Walks may go around the cycle 1 2 any number of times and pass
+		// through the target 2 on the way
+		g := mockGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 1, 2}})
+		walks, err := KShortestWalks(g, 0, 2, 4)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find k shortest walks: %v", err))
+		}
+		checkPaths(t, g, walks, 0, 2, false, []float64{2, 5, 8, 11})
+		checkInts(t, "Second shortest walk", walks[1].Nodes, []int{0, 1, 2, 1, 2})
+
+		// Without cycles the walks are the loopless paths
+		g = mockGraph(t, 6, yen)
+		walks, _ = KShortestWalks(g, 0, 5, 100)
+		checkPaths(t, g, walks, 0, 5, true, []float64{5, 7, 8, 8, 8, 11, 11})
+
+		g = mockGraph(t, 1, []mockEdge{{0, 0, 1}})
+		walks, _ = KShortestWalks(g, 0, 0, 3)
+		checkPaths(t, g, walks, 0, 0, false, []float64{0, 1, 2})
+	})
+
+	t.Run("Simple paths", func(t *testing.T) {
+		g := mockGraph(t, 6, yen)
+		paths, err := SimplePaths(g, 0, 5, 0, 0)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find simple paths: %v", err))
+		}
+		checkPaths(t, g, paths, 0, 5, true, []float64{5, 7, 8, 8, 8, 11, 11})
+
+		paths, _ = SimplePaths(g, 0, 5, 3, 0)
+		checkPaths(t, g, paths, 0, 5, true, []float64{5, 7, 8})
+		paths, _ = SimplePaths(g, 0, 5, 0, 8)
+		checkPaths(t, g, paths, 0, 5, true, []float64{5, 7, 8, 8, 8})
+		paths, _ = SimplePaths(g, 0, 5, 3, 7)
+		checkPaths(t, g, paths, 0, 5, true, []float64{5, 7})
+
+		// A negative weight still counts toward the cost limit
+		g = mockUndirectedGraph(t, 3, []mockEdge{{0, 1, 5}, {1, 2, -4}, {0, 2, 2}})
+		paths, _ = SimplePaths(g, 0, 2, 0, 1.5)
+		checkPaths(t, g, paths, 0, 2, true, []float64{1})
+
+		paths, _ = SimplePaths(mockGraph(t, 2, nil), 0, 1, 0, 0)
+		if len(paths) != 0 {
+			t.Fatalf(fmt.Sprintf("Disconnected nodes should have no paths but have %v", paths))
+		}
+	})
+
+	t.Run("Random graphs", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 100; i++ {
+			n := 2 + r.Intn(5)
+			var edges []mockEdge
+			for a := 0; a < n; a++ {
+				for b := 0; b < n; b++ {
+					if a != b && r.Intn(2) == 0 {
+						edges = append(edges, mockEdge{a, b, float64(1 + r.Intn(4))})
+					}
+				}
+			}
+			g := mockGraph(t, n, edges)
+			source, target := r.Intn(n), r.Intn(n)
+
+			// Brute force every walk costing at most limit
+			const limit = 12
+			var costs, simple []float64
+			var extend func(id int, cost float64, visited map[int]bool)
+			extend = func(id int, cost float64, visited map[int]bool) {
+				if id == target {
+					costs = append(costs, cost)
+					if visited != nil {
+						simple = append(simple, cost)
+					}
+				}
+				for _, e := range edges {
+					if e.from != id || cost+e.w > limit {
+						continue
+					}
+					next := visited
+					if visited != nil && visited[e.to] {
+						next = nil
+					} else if visited != nil {
+						next = make(map[int]bool)
+						for k := range visited {
+							next[k] = true
+						}
+						next[e.to] = true
+					}
+					extend(e.to, cost+e.w, next)
+				}
+			}
+			extend(source, 0, map[int]bool{source: true})
+			sort.Float64s(costs)
+			sort.Float64s(simple)
+
+			all, err := SimplePaths(g, source, target, 0, 0)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find simple paths: %v", err))
+			}
+			var allCosts []float64
+			for _, p := range all {
+				allCosts = append(allCosts, p.Cost)
+			}
+			checkPaths(t, g, all, source, target, true, allCosts)
+			bounded, _ := SimplePaths(g, source, target, 0, limit)
+			checkPaths(t, g, bounded, source, target, true, simple)
+
+			const k = 8
+			want := allCosts
+			if len(want) > k {
+				want = want[:k]
+			}
+			paths, err := KShortestPaths(g, source, target, k)
+			var noPath *NoPathError
+			if len(all) == 0 {
+				if !errors.As(err, &noPath) {
+					t.Fatalf(fmt.Sprintf("Unreachable target should fail with NoPathError but got %v", err))
+				}
+				continue
+			}
+			checkPaths(t, g, paths, source, target, true, want)
+
+			walks, err := KShortestWalks(g, source, target, k)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find k shortest walks: %v", err))
+			}
+			want = costs
+			if len(want) > k {
+				want = want[:k]
+			}
+			if len(walks) > len(want) {
+				// Walks beyond those found by brute force cost more than
+				// the limit
+				for _, w := range walks[len(want):] {
+					if w.Cost <= limit {
+						t.Fatalf(fmt.Sprintf("Walk %v should cost more than %d", w, limit))
+					}
+				}
+				walks = walks[:len(want)]
+			}
+			checkPaths(t, g, walks, source, target, false, want)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		g := mockGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, -1}})
+		var negative *NegativeWeightError
+		if _, err := KShortestPaths(g, 0, 2, 2); !errors.As(err, &negative) {
+			t.Fatalf("K shortest paths with a negative weight should fail with NegativeWeightError")
+		}
+		if _, err := KShortestWalks(g, 0, 2, 2); !errors.As(err, &negative) {
+			t.Fatalf("K shortest walks with a negative weight should fail with NegativeWeightError")
+		}
+
+		var noPath *NoPathError
+		if _, err := KShortestWalks(mockGraph(t, 2, nil), 1, 0, 2); !errors.As(err, &noPath) {
+			t.Fatalf("K shortest walks to an unreachable node should fail with NoPathError")
+		}
+
+		var noNode *structures.NoNodeError
+		for name, alg := range map[string]func() error{
+			"KShortestPaths": func() error { _, err := KShortestPaths(g, 0, 3, 2); return err },
+			"KShortestWalks": func() error { _, err := KShortestWalks(g, 3, 0, 2); return err },
+			"SimplePaths":    func() error { _, err := SimplePaths(g, 0, 3, 0, 0); return err },
+		} {
+			if err := alg(); !errors.As(err, &noNode) {
+				t.Fatalf(fmt.Sprintf("%s with a missing node should fail with NoNodeError", name))
+			}
+		}
+	})
+}
+
+// checkPaths checks that the paths run along edges of g from source to target
+// with the wanted costs in order, that no two are the same and, if simple is
+// set, that none repeats a node
+func checkPaths(t *testing.T, g *structures.Graph, paths []*Path, source, target int, simple bool, costs []float64) {
+	t.Helper()
+	if len(paths) != len(costs) {
+		t.Fatalf(fmt.Sprintf("Paths should cost %v but are %v", costs, paths))
+	}
+	seen := make(map[string]bool)
+	for i, p := range paths {
+		if p.Nodes[0] != source || p.Nodes[len(p.Nodes)-1] != target || p.Cost != costs[i] {
+			t.Fatalf(fmt.Sprintf("Path %v should run from %d to %d and cost %f", p, source, target, costs[i]))
+		}
+		if got := pathCost(g, p.Nodes); got != p.Cost {
+			t.Fatalf(fmt.Sprintf("Path %v should cost %f as reported but costs %f", p.Nodes, p.Cost, got))
+		}
+		for _, k := range p.Edges() {
+			if _, err := g.GetEdgeByNodeID(k.From, k.To); err != nil {
+				t.Fatalf(fmt.Sprintf("Path %v takes missing edge %v", p.Nodes, k))
+			}
+		}
+		if key := fmt.Sprint(p.Nodes); seen[key] {
+			t.Fatalf(fmt.Sprintf("Path %v is found twice", p.Nodes))
+		} else {
+			seen[key] = true
+		}
+		visited := make(map[int]bool)
+		for _, id := range p.Nodes {
+			if simple && visited[id] {
+				t.Fatalf(fmt.Sprintf("Path %v repeats node %d", p.Nodes, id))
+			}
+			visited[id] = true
+		}
+	}
+}
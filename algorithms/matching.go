@@ -0,0 +1,359 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// OddCycleError states that an algorithm requiring a bipartite graph found a
+// cycle of odd length. Cycle holds the node IDs of the cycle in order, and the
+// last node is joined back to the first
+type OddCycleError struct {
+	Cycle []int
+	Err   error
+}
+
+// Error serves the error message for OddCycleError
+func (e *OddCycleError) Error() string {
+	return fmt.Sprintf("Odd cycle through nodes %v: %v", e.Cycle, e.Err)
+}
+
+func (e *OddCycleError) Unwrap() error { return e.Err }
+
+// PerfectMatchingError states that a node cannot be matched by any perfect
+// matching
+type PerfectMatchingError struct {
+	id  int
+	Err error
+}
+
+// Error serves the error message for PerfectMatchingError
+func (e *PerfectMatchingError) Error() string {
+	return fmt.Sprintf("Node %d cannot be perfectly matched: %v", e.id, e.Err)
+}
+
+func (e *PerfectMatchingError) Unwrap() error { return e.Err }
+
+// Bipartition is a two-coloring of a graph viewed as undirected, so that every
+// edge joins nodes of different sides
+type Bipartition struct {
+	// Side holds the side, 0 or 1, of each node ID. The first node of each
+	// connected component in graph order is on side 0
+	Side map[int]int `json:"side"`
+	// Left and Right hold the IDs of the nodes on sides 0 and 1 in graph order
+	Left  []int `json:"left"`
+	Right []int `json:"right"`
+}
+
+// Bipartite two-colors g viewed as undirected with a breadth-first search from
+// the first node of each connected component. If an edge joins two nodes of the
+// same color, g is not bipartite and an *OddCycleError holding the cycle
+// through the edge and the search tree is returned. A self loop is an odd
+// cycle of one node
+func Bipartite(g *structures.Graph, v *Visitor) (*Bipartition, error) {
+	b := &Bipartition{Side: make(map[int]int, len(g.Nodes))}
+	parent := make(map[int]int, len(g.Nodes))
+	for _, root := range g.Nodes {
+		if _, ok := b.Side[root.ID]; ok {
+			continue
+		}
+		b.Side[root.ID] = 0
+		parent[root.ID] = root.ID
+		v.discoverNode(root.ID)
+
+		queue := []*structures.Node{root}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			v.examineNode(n.ID)
+			for _, e := range g.OutEdges(n) {
+				if e.Nodes[1].ID == n.ID {
+					return nil, &OddCycleError{[]int{n.ID}, nil}
+				}
+			}
+
+			for _, nb := range undirectedNeighbors(g, n) {
+				v.examineEdge(nb.e)
+				side, ok := b.Side[nb.n.ID]
+				if !ok {
+					b.Side[nb.n.ID] = 1 - b.Side[n.ID]
+					parent[nb.n.ID] = n.ID
+					v.discoverNode(nb.n.ID)
+					queue = append(queue, nb.n)
+				} else if side == b.Side[n.ID] {
+					return nil, &OddCycleError{oddCycle(parent, n.ID, nb.n.ID), nil}
+				}
+			}
+			v.finishNode(n.ID)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if b.Side[n.ID] == 0 {
+			b.Left = append(b.Left, n.ID)
+		} else {
+			b.Right = append(b.Right, n.ID)
+		}
+	}
+	return b, nil
+}
+
+// oddCycle returns the cycle formed by the tree paths from a and b of the same
+// color up to their lowest common ancestor, and the edge joining a and b
+func oddCycle(parent map[int]int, a, b int) []int {
+	onPath := map[int]bool{}
+	for id := a; ; id = parent[id] {
+		onPath[id] = true
+		if parent[id] == id {
+			break
+		}
+	}
+
+	var fromB []int
+	lca := b
+	for !onPath[lca] {
+		fromB = append(fromB, lca)
+		lca = parent[lca]
+	}
+
+	var cycle []int
+	for id := a; id != lca; id = parent[id] {
+		cycle = append(cycle, id)
+	}
+	cycle = append(cycle, lca)
+	reverseInts(fromB)
+	return append(cycle, fromB...)
+}
+
+// Matching is a set of edges of a graph viewed as undirected no two of which
+// share a node
+type Matching struct {
+	// Edges holds the matched edges, each in a direction in which it is stored
+	// in the graph
+	Edges []EdgeKey `json:"edges"`
+	// Mate holds the node matched to each matched node ID
+	Mate map[int]int `json:"mate"`
+	// Weight is the total weight of the matched edges
+	Weight float64 `json:"weight"`
+}
+
+// newMatching collects a matching from the edges joining each left node to its
+// mate and selects them
+func newMatching(v *Visitor, edges []*structures.Edge) *Matching {
+	m := &Matching{Mate: make(map[int]int, 2*len(edges))}
+	for _, e := range edges {
+		a, b := e.Nodes[0].ID, e.Nodes[1].ID
+		m.Edges = append(m.Edges, KeyOf(e))
+		m.Mate[a] = b
+		m.Mate[b] = a
+		m.Weight += e.Weight
+		v.selectEdge(e)
+	}
+	return m
+}
+
+// HopcroftKarp finds a maximum cardinality matching of bipartite graph g
+// viewed as undirected. Each phase finds the shortest augmenting paths from the
+// free nodes of the left side with a breadth-first search, then augments a
+// maximal set of disjoint ones with depth-first searches through the layers.
+// If g is not bipartite, an *OddCycleError is returned
+func HopcroftKarp(g *structures.Graph, v *Visitor) (*Matching, error) {
+	b, err := Bipartite(g, nil)
+	if err != nil {
+		return nil, fmt.Errorf("hopcroft-karp: %w", err)
+	}
+
+	neighbors := make(map[int][]neighbor, len(b.Left))
+	mate := make(map[int]*neighbor, len(g.Nodes))
+	for _, id := range b.Left {
+		n, _ := g.GetNodeByID(id)
+		neighbors[id] = undirectedNeighbors(g, n)
+	}
+
+	dist := make(map[int]int, len(b.Left))
+	for {
+		// Layer the left nodes by the length of the shortest alternating path
+		// to them from a free left node
+		found := false
+		var queue []int
+		for _, id := range b.Left {
+			if mate[id] == nil {
+				dist[id] = 0
+				v.discoverNode(id)
+				queue = append(queue, id)
+			} else {
+				dist[id] = math.MaxInt32
+			}
+		}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			v.examineNode(id)
+			for _, nb := range neighbors[id] {
+				v.examineEdge(nb.e)
+				next := mate[nb.n.ID]
+				if next == nil {
+					found = true
+				} else if dist[next.n.ID] == math.MaxInt32 {
+					dist[next.n.ID] = dist[id] + 1
+					v.discoverNode(next.n.ID)
+					queue = append(queue, next.n.ID)
+				}
+			}
+			v.finishNode(id)
+		}
+		if !found {
+			break
+		}
+
+		var augment func(id int) bool
+		augment = func(id int) bool {
+			for i := range neighbors[id] {
+				nb := &neighbors[id][i]
+				next := mate[nb.n.ID]
+				if next == nil || dist[next.n.ID] == dist[id]+1 && augment(next.n.ID) {
+					n, _ := g.GetNodeByID(id)
+					mate[id] = nb
+					mate[nb.n.ID] = &neighbor{n, nb.e}
+					return true
+				}
+			}
+			// No augmenting path passes through id in this phase
+			dist[id] = math.MaxInt32
+			return false
+		}
+		for _, id := range b.Left {
+			if mate[id] == nil {
+				augment(id)
+			}
+		}
+	}
+
+	var edges []*structures.Edge
+	for _, id := range b.Left {
+		if nb := mate[id]; nb != nil {
+			edges = append(edges, nb.e)
+		}
+	}
+	return newMatching(v, edges), nil
+}
+
+// Hungarian finds a perfect matching of greatest total weight in bipartite
+// graph g viewed as undirected with the Hungarian algorithm, which adds the
+// nodes of the smaller side one at a time along a cheapest augmenting path
+// under node potentials. Two nodes joined in both directions are matched by
+// their heaviest edge. If g is not bipartite, an *OddCycleError is returned,
+// and if g has no perfect matching, a *PerfectMatchingError holding a node
+// that cannot be matched is returned
+func Hungarian(g *structures.Graph, v *Visitor) (*Matching, error) {
+	b, err := Bipartite(g, nil)
+	if err != nil {
+		return nil, fmt.Errorf("hungarian: %w", err)
+	}
+	rows, cols := b.Left, b.Right
+	if len(rows) > len(cols) {
+		rows, cols = cols, rows
+	}
+
+	// edge holds the heaviest edge joining each row to each column, and the
+	// cost of an assignment is the negated weight of its edge
+	col := make(map[int]int, len(cols))
+	for j, id := range cols {
+		col[id] = j + 1
+	}
+	edge := make([][]*structures.Edge, len(rows)+1)
+	for i, id := range rows {
+		n, _ := g.GetNodeByID(id)
+		edge[i+1] = make([]*structures.Edge, len(cols)+1)
+		for _, edges := range [][]*structures.Edge{g.OutEdges(n), g.InEdges(n)} {
+			for _, e := range edges {
+				j := col[otherNode(e, id).ID]
+				if edge[i+1][j] == nil || e.Weight > edge[i+1][j].Weight {
+					edge[i+1][j] = e
+				}
+			}
+		}
+	}
+	cost := func(i, j int) float64 {
+		if edge[i][j] == nil {
+			return math.Inf(1)
+		}
+		return -edge[i][j].Weight
+	}
+
+	// Rows and columns are numbered from 1, and column 0 holds the row being
+	// added. assigned holds the row assigned to each column, or 0 if none
+	u := make([]float64, len(rows)+1)
+	p := make([]float64, len(cols)+1)
+	assigned := make([]int, len(cols)+1)
+	way := make([]int, len(cols)+1)
+	for i := 1; i <= len(rows); i++ {
+		v.examineNode(rows[i-1])
+		assigned[0] = i
+		minCost := make([]float64, len(cols)+1)
+		used := make([]bool, len(cols)+1)
+		for j := range minCost {
+			minCost[j] = math.Inf(1)
+		}
+
+		// Grow a tree of alternating paths from row i until it reaches a free
+		// column
+		j0 := 0
+		for assigned[j0] != 0 {
+			used[j0] = true
+			i0 := assigned[j0]
+			delta, j1 := math.Inf(1), 0
+			for j := 1; j <= len(cols); j++ {
+				if used[j] {
+					continue
+				}
+				if c := cost(i0, j) - u[i0] - p[j]; c < minCost[j] {
+					v.examineEdge(edge[i0][j])
+					minCost[j] = c
+					way[j] = j0
+				}
+				if minCost[j] < delta {
+					delta, j1 = minCost[j], j
+				}
+			}
+			if math.IsInf(delta, 1) {
+				return nil, fmt.Errorf("hungarian: %w", &PerfectMatchingError{rows[i-1], nil})
+			}
+
+			for j := 0; j <= len(cols); j++ {
+				if used[j] {
+					u[assigned[j]] += delta
+					p[j] -= delta
+				} else {
+					minCost[j] -= delta
+				}
+			}
+			j0 = j1
+			v.discoverNode(cols[j0-1])
+		}
+
+		// Flip the alternating path back to row i
+		for j0 != 0 {
+			j1 := way[j0]
+			assigned[j0] = assigned[j1]
+			j0 = j1
+		}
+		v.finishNode(rows[i-1])
+	}
+
+	// Every row is matched, so any node left over on the larger side cannot be
+	// matched
+	for j := 1; j <= len(cols); j++ {
+		if assigned[j] == 0 {
+			return nil, fmt.Errorf("hungarian: %w", &PerfectMatchingError{cols[j-1], nil})
+		}
+	}
+
+	edges := make([]*structures.Edge, len(rows))
+	for j := 1; j <= len(cols); j++ {
+		edges[assigned[j]-1] = edge[assigned[j]][j]
+	}
+	return newMatching(v, edges), nil
+}
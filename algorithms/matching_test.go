@@ -0,0 +1,172 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestBipartite(t *testing.T) {
+	log.Printf("Testing bipartite detection")
+
+	// A 6-cycle with a pendant node 6 and a separate edge 7-8
+	g := mockUndirectedGraph(t, 9, []mockEdge{
+		{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 5, 1}, {5, 0, 1},
+		{3, 6, 1}, {7, 8, 1},
+	})
+	b, err := Bipartite(g, nil)
+	if err != nil {
+		t.Fatalf(fmt.Sprintf("Could not two-color bipartite graph: %v", err))
+	}
+	checkInts(t, "Left side", b.Left, []int{0, 2, 4, 6, 7})
+	checkInts(t, "Right side", b.Right, []int{1, 3, 5, 8})
+
+	t.Run("Odd cycles", func(t *testing.T) {
+		// Closing the 6-cycle with chord 0-2 leaves triangle 0 1 2, and chord
+		// 0-4 leaves a 5-cycle
+		for _, chord := range []mockEdge{{0, 2, 1}, {4, 0, 1}} {
+			g := mockUndirectedGraph(t, 9, []mockEdge{
+				{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 5, 1}, {5, 0, 1}, chord,
+			})
+			var odd *OddCycleError
+			if _, err := Bipartite(g, nil); !errors.As(err, &odd) {
+				t.Fatalf("A graph with an odd cycle should fail with OddCycleError")
+			}
+			checkOddCycle(t, g, odd.Cycle)
+		}
+
+		g := mockGraph(t, 2, []mockEdge{{0, 1, 1}, {1, 1, 1}})
+		var odd *OddCycleError
+		if _, err := Bipartite(g, nil); !errors.As(err, &odd) {
+			t.Fatalf("A graph with a self loop should fail with OddCycleError")
+		}
+		checkInts(t, "Self loop", odd.Cycle, []int{1})
+	})
+}
+
+func TestMatching(t *testing.T) {
+	log.Printf("Testing bipartite matching")
+
+	t.Run("Hopcroft-Karp", func(t *testing.T) {
+		// Left nodes 0 through 3 and right nodes 4 through 7. Greedily matching
+		// 0-5 and 1-4 forces augmenting paths to find the perfect matching
+		g := mockUndirectedGraph(t, 8, []mockEdge{
+			{0, 5, 1}, {0, 4, 1}, {1, 4, 1}, {1, 6, 1}, {2, 5, 1}, {3, 6, 1}, {3, 7, 1},
+		})
+		m, err := HopcroftKarp(g, nil)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find maximum matching: %v", err))
+		}
+		checkMatching(t, g, m, 4)
+
+		// Nodes 4 and 5 compete for the single neighbor of 0, 1 and 2
+		g = mockGraph(t, 6, []mockEdge{{0, 4, 1}, {4, 1, 1}, {2, 4, 1}, {3, 5, 1}, {2, 5, 1}})
+		m, err = HopcroftKarp(g, nil)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find maximum matching: %v", err))
+		}
+		checkMatching(t, g, m, 2)
+
+		g = mockUndirectedGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}})
+		var odd *OddCycleError
+		if _, err = HopcroftKarp(g, nil); !errors.As(err, &odd) {
+			t.Fatalf("Matching a triangle should fail with OddCycleError")
+		}
+	})
+
+	t.Run("Hungarian", func(t *testing.T) {
+		// Workers 0 through 2 and jobs 3 through 5. The heaviest assignment
+		// is 0-5, 1-4 and 2-3 with a weight of 24
+		weights := [][]float64{{7, 5, 11}, {5, 4, 1}, {9, 3, 2}}
+		var edges []mockEdge
+		for w := range weights {
+			for j, weight := range weights[w] {
+				edges = append(edges, mockEdge{w, j + 3, weight})
+			}
+		}
+		g := mockUndirectedGraph(t, 6, edges)
+		m, err := Hungarian(g, nil)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find maximum weight matching: %v", err))
+		}
+		checkMatching(t, g, m, 3)
+		if m.Weight != 24 {
+			t.Fatalf(fmt.Sprintf("Matching should weigh 24 but weighs %f", m.Weight))
+		}
+		for w, j := range map[int]int{0: 5, 1: 4, 2: 3} {
+			if m.Mate[w] != j {
+				t.Fatalf(fmt.Sprintf("Worker %d should be matched to job %d", w, j))
+			}
+		}
+
+		// Negative weights are allowed, and a missing edge is never matched
+		g = mockGraph(t, 4, []mockEdge{{0, 2, -1}, {0, 3, 5}, {1, 3, -2}})
+		m, err = Hungarian(g, nil)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find maximum weight matching: %v", err))
+		}
+		checkMatching(t, g, m, 2)
+		if m.Weight != -3 {
+			t.Fatalf(fmt.Sprintf("Matching should weigh -3 but weighs %f", m.Weight))
+		}
+
+		// Nodes 0 and 1 can only be matched to node 3. The sides have three
+		// nodes each, or four and three with isolated node 6
+		var perfect *PerfectMatchingError
+		for _, n := range []int{6, 7} {
+			g = mockGraph(t, n, []mockEdge{{0, 3, 1}, {1, 3, 1}, {2, 4, 1}, {2, 5, 1}})
+			if _, err = Hungarian(g, nil); !errors.As(err, &perfect) {
+				t.Fatalf("Matching without a perfect matching should fail with PerfectMatchingError")
+			}
+		}
+	})
+}
+
+// checkOddCycle checks that cycle has odd length and follows edges of g in
+// either direction
+func checkOddCycle(t *testing.T, g *structures.Graph, cycle []int) {
+	t.Helper()
+	if len(cycle)%2 == 0 {
+		t.Fatalf(fmt.Sprintf("Cycle %v should have odd length", cycle))
+	}
+	for i, id := range cycle {
+		next := cycle[(i+1)%len(cycle)]
+		if _, err := g.GetEdgeByNodeID(id, next); err != nil {
+			if _, err = g.GetEdgeByNodeID(next, id); err != nil {
+				t.Fatalf(fmt.Sprintf("Cycle %v should follow graph edges", cycle))
+			}
+		}
+	}
+}
+
+// checkMatching checks that m is a matching of g with the wanted number of
+// edges whose mates and weight agree with its edges
+func checkMatching(t *testing.T, g *structures.Graph, m *Matching, size int) {
+	t.Helper()
+	if len(m.Edges) != size {
+		t.Fatalf(fmt.Sprintf("Matching should have %d edges but has %v", size, m.Edges))
+	}
+
+	matched := make(map[int]bool)
+	weight := 0.0
+	for _, k := range m.Edges {
+		e, err := g.GetEdgeByNodeID(k.From, k.To)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Matched edge %v should be a graph edge", k))
+		}
+		if matched[k.From] || matched[k.To] {
+			t.Fatalf(fmt.Sprintf("Matched edges %v should not share nodes", m.Edges))
+		}
+		matched[k.From], matched[k.To] = true, true
+		if m.Mate[k.From] != k.To || m.Mate[k.To] != k.From {
+			t.Fatalf(fmt.Sprintf("Nodes of matched edge %v should be mates", k))
+		}
+		weight += e.Weight
+	}
+	if len(m.Mate) != 2*size || weight != m.Weight {
+		t.Fatalf(fmt.Sprintf("Matching should weigh %f but weighs %f", weight, m.Weight))
+	}
+}
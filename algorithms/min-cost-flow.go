@@ -0,0 +1,285 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// costScalingFactor is the factor by which cost scaling tightens its
+// optimality bound between refinements
+const costScalingFactor = 4
+
+// IntegralCostError states that an algorithm requiring integer edge costs
+// found a fractional cost
+type IntegralCostError struct {
+	edge EdgeKey
+	c    float64
+	Err  error
+}
+
+// Error serves the error message for IntegralCostError
+func (e *IntegralCostError) Error() string {
+	return fmt.Sprintf(
+		"Fractional cost %f on edge from %d to %d: %v",
+		e.c, e.edge.From, e.edge.To, e.Err,
+	)
+}
+
+func (e *IntegralCostError) Unwrap() error { return e.Err }
+
+// CostFlow holds a maximum flow of least total cost, where the capacity of each
+// edge is its weight and the cost per unit of flow along it is its cost
+type CostFlow struct {
+	Flow
+	// Cost is the total cost of the flow, the sum over all edges of their flow
+	// times their cost
+	Cost float64 `json:"cost"`
+}
+
+// costResult collects the flow, value, minimum cut and cost of the network
+func (f *flowNetwork) costResult() *CostFlow {
+	r := &CostFlow{Flow: *f.result()}
+	for _, a := range f.arcs {
+		if a.edge != nil {
+			r.Cost += a.flow * a.cost
+		}
+	}
+	return r
+}
+
+// SuccessiveShortestPaths finds a maximum flow of least cost from source to
+// sink in g by repeatedly sending flow along a cheapest residual path. Node
+// potentials keep the reduced costs of residual arcs non-negative, so each path
+// is found by Dijkstra. Capacities must not be negative. Costs may be negative,
+// but if a cycle of edges with capacity has negative total cost, a
+// *NegativeCycleError holding the cycle is returned
+func SuccessiveShortestPaths(g *structures.Graph, v *Visitor, source, sink int) (*CostFlow, error) {
+	f, err := newFlowNetwork(g, v, source, sink)
+	if err != nil {
+		return nil, fmt.Errorf("successive shortest paths: %w", err)
+	}
+
+	// The cheapest costs from a virtual source are the initial potentials.
+	// Edges without capacity are not residual arcs, so they are left out
+	dist, parent := virtualSourceDistances(g)
+	cycle := bellmanFord(g, nil, dist, parent, func(e *structures.Edge) float64 {
+		if e.Weight == 0 {
+			return math.Inf(1)
+		}
+		return e.Cost
+	})
+	if cycle != nil {
+		return nil, &NegativeCycleError{cycle, nil}
+	}
+	potential := make([]float64, len(g.Nodes))
+	for i, n := range g.Nodes {
+		potential[i] = dist[n.ID]
+	}
+
+	parentArc := make([]int, len(g.Nodes))
+	for {
+		d := f.reducedDistances(potential, parentArc)
+		if math.IsInf(d[f.sink], 1) {
+			break
+		}
+
+		// Nodes costlier than the sink are raised by the sink's cost so that
+		// reduced costs stay non-negative
+		for i := range potential {
+			potential[i] += math.Min(d[i], d[f.sink])
+		}
+
+		var path []int
+		for w := f.sink; w != f.source; w = f.arcs[parentArc[w]^1].to {
+			path = append(path, parentArc[w])
+		}
+		f.augmentPath(path)
+	}
+
+	return f.costResult(), nil
+}
+
+// reducedDistances returns the cost of the cheapest residual path from the
+// source to each node under the reduced costs of potential, or +Inf for nodes
+// that cannot be reached. parent is filled with the arc that reaches each node
+func (f *flowNetwork) reducedDistances(potential []float64, parent []int) []float64 {
+	dist := make([]float64, len(f.adj))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	settled := make([]bool, len(f.adj))
+	dist[f.source] = 0
+	f.v.discoverNode(f.id(f.source))
+
+	q := &priorityQueue{}
+	q.push(f.source, 0)
+	for q.Len() > 0 {
+		u := q.pop().id
+		if settled[u] {
+			continue
+		}
+		settled[u] = true
+		f.v.examineNode(f.id(u))
+
+		for _, a := range f.adj[u] {
+			if f.residual(a) <= flowEpsilon {
+				continue
+			}
+			if e := f.arcs[a].edge; e != nil {
+				f.v.examineEdge(e)
+			}
+
+			// Rounding can leave a zero reduced cost slightly negative
+			w := f.arcs[a].to
+			rc := math.Max(0, f.arcs[a].cost+potential[u]-potential[w])
+			if d := dist[u] + rc; d < dist[w] {
+				if math.IsInf(dist[w], 1) {
+					f.v.discoverNode(f.id(w))
+				}
+				dist[w] = d
+				parent[w] = a
+				q.push(w, d)
+			}
+		}
+		f.v.finishNode(f.id(u))
+	}
+	return dist
+}
+
+// CostScaling finds a maximum flow of least cost from source to sink in g with
+// Goldberg and Tarjan's cost scaling. A maximum flow is found first, and its
+// cost is then lowered by pushing flow around residual cycles, which keeps its
+// value. Each refinement makes the flow optimal to within a smaller bound on
+// the reduced cost of residual arcs. Capacities must not be negative. Costs may
+// be negative, including around cycles, but must be integers, otherwise an
+// *IntegralCostError is returned
+func CostScaling(g *structures.Graph, v *Visitor, source, sink int) (*CostFlow, error) {
+	for _, n := range g.Nodes {
+		for _, e := range n.Edges {
+			if e.Cost != math.Trunc(e.Cost) {
+				return nil, fmt.Errorf("cost scaling: %w", &IntegralCostError{KeyOf(e), e.Cost, nil})
+			}
+		}
+	}
+	f, err := newFlowNetwork(g, v, source, sink)
+	if err != nil {
+		return nil, fmt.Errorf("cost scaling: %w", err)
+	}
+	f.dinic()
+
+	// Costs are scaled by more than the number of nodes, so that a flow within
+	// one scaled unit of optimal is optimal for integer costs
+	scale := int64(len(g.Nodes) + 1)
+	cost := make([]int64, len(f.arcs))
+	eps := int64(0)
+	for a := range f.arcs {
+		cost[a] = int64(f.arcs[a].cost) * scale
+		if cost[a] > eps {
+			eps = cost[a]
+		} else if -cost[a] > eps {
+			eps = -cost[a]
+		}
+	}
+
+	price := make([]int64, len(g.Nodes))
+	for eps > 1 {
+		eps /= costScalingFactor
+		if eps < 1 {
+			eps = 1
+		}
+		f.refine(cost, price, eps)
+	}
+
+	return f.costResult(), nil
+}
+
+// refine makes the flow eps-optimal, so that no residual arc has a reduced cost
+// below -eps, given a flow that is costScalingFactor*eps-optimal. Residual arcs
+// of negative reduced cost are saturated, and the resulting excesses are
+// pushed along such arcs, lowering the prices of nodes that cannot push
+func (f *flowNetwork) refine(cost, price []int64, eps int64) {
+	reduced := func(u, a int) int64 {
+		return cost[a] + price[u] - price[f.arcs[a].to]
+	}
+
+	excess := make([]float64, len(f.adj))
+	for u := range f.adj {
+		for _, a := range f.adj[u] {
+			if d := f.residual(a); d > flowEpsilon && reduced(u, a) < 0 {
+				f.push(a, d)
+				excess[u] -= d
+				excess[f.arcs[a].to] += d
+			}
+		}
+	}
+
+	active := make([]bool, len(f.adj))
+	var queue []int
+	activate := func(u int) {
+		if !active[u] && excess[u] > flowEpsilon {
+			active[u] = true
+			queue = append(queue, u)
+			f.v.discoverNode(f.id(u))
+		}
+	}
+	for u := range f.adj {
+		activate(u)
+	}
+
+	// Each price drops by at most (costScalingFactor+1)*n*eps during a
+	// refinement, so one more eps per node leaves room for the bound
+	start := append([]int64(nil), price...)
+	maxDrop := (costScalingFactor + 2) * int64(len(f.adj)) * eps
+
+	next := make([]int, len(f.adj))
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		active[u] = false
+		f.v.examineNode(f.id(u))
+
+		for excess[u] > flowEpsilon {
+			if next[u] == len(f.adj[u]) {
+				// Lower the price of u until its cheapest residual arc
+				// becomes admissible
+				highest := int64(math.MinInt64)
+				for _, a := range f.adj[u] {
+					if f.residual(a) > flowEpsilon {
+						if p := price[f.arcs[a].to] - cost[a]; p > highest {
+							highest = p
+						}
+					}
+				}
+				if highest == math.MinInt64 || start[u]-(highest-eps) > maxDrop {
+					// Excess could always return the way it came, and no
+					// price drops by more than maxDrop, so excess without a
+					// residual arc or that would lower u further is rounding
+					// error
+					excess[u] = 0
+					break
+				}
+				price[u] = highest - eps
+				next[u] = 0
+				continue
+			}
+
+			a := f.adj[u][next[u]]
+			if f.residual(a) <= flowEpsilon || reduced(u, a) >= 0 {
+				next[u]++
+				continue
+			}
+			if e := f.arcs[a].edge; e != nil {
+				f.v.examineEdge(e)
+			}
+			w := f.arcs[a].to
+			d := math.Min(excess[u], f.residual(a))
+			f.push(a, d)
+			excess[u] -= d
+			excess[w] += d
+			activate(w)
+		}
+		f.v.finishNode(f.id(u))
+	}
+}
@@ -0,0 +1,156 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestMinCostFlow(t *testing.T) {
+	log.Printf("Testing minimum cost flow")
+
+	// Workers 1 through 3 are assigned to jobs 4 through 6 from source 0 to
+	// sink 7. The cheapest assignment is 1-5, 2-4 and 3-6 at a cost of 5
+	costs := [][]float64{{4, 1, 3}, {2, 0, 5}, {3, 2, 2}}
+	assignment := func(t *testing.T) *structures.Graph {
+		g := mockGraph(t, 8, nil)
+		for w := 1; w <= 3; w++ {
+			g.SetEdgeByNodeID(0, w, 1, "n", "n", false)
+			g.SetEdgeByNodeID(w+3, 7, 1, "n", "n", false)
+			for j := 4; j <= 6; j++ {
+				g.SetEdgeByNodeID(w, j, 1, "n", "n", false)
+				g.SetEdgeCostByNodeID(w, j, costs[w-1][j-4], false)
+			}
+		}
+		return g
+	}
+
+	// 0 -> 1 -> 2 carries one unit of flow, and cycle 1 -> 2 -> 1 has a cost of
+	// -2 and capacity left over
+	cycle := func(t *testing.T) *structures.Graph {
+		g := mockGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, 2}, {2, 1, 1}})
+		g.SetEdgeCostByNodeID(0, 1, 1, false)
+		g.SetEdgeCostByNodeID(1, 2, 1, false)
+		g.SetEdgeCostByNodeID(2, 1, -3, false)
+		return g
+	}
+
+	algs := map[string]func(*structures.Graph, *Visitor, int, int) (*CostFlow, error){
+		"Successive shortest paths": SuccessiveShortestPaths,
+		"Cost scaling":              CostScaling,
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			g := assignment(t)
+			f, err := alg(g, nil, 0, 7)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find minimum cost flow: %v", err))
+			}
+			checkFlow(t, g, &f.Flow, 3)
+			checkFlowCost(t, g, f, 5)
+			for _, k := range []EdgeKey{{1, 5}, {2, 4}, {3, 6}} {
+				if f.EdgeFlow[k] != 1 {
+					t.Fatalf(fmt.Sprintf("Worker %d should be assigned to job %d", k.From, k.To))
+				}
+			}
+
+			// Agrees with maximum flow when every cost is zero
+			g = mockGraph(t, 6, []mockEdge{
+				{0, 1, 16}, {0, 2, 13}, {1, 2, 10}, {2, 1, 4}, {1, 3, 12},
+				{3, 2, 9}, {2, 4, 14}, {4, 3, 7}, {3, 5, 20}, {4, 5, 4},
+			})
+			f, err = alg(g, nil, 0, 5)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find minimum cost flow: %v", err))
+			}
+			checkFlow(t, g, &f.Flow, 23)
+		})
+	}
+
+	t.Run("Negative cycles", func(t *testing.T) {
+		g := cycle(t)
+		f, err := CostScaling(g, nil, 0, 2)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find minimum cost flow: %v", err))
+		}
+		checkFlow(t, g, &f.Flow, 1)
+		checkFlowCost(t, g, f, 0)
+
+		var negative *NegativeCycleError
+		if _, err = SuccessiveShortestPaths(g, nil, 0, 2); !errors.As(err, &negative) {
+			t.Fatalf("Successive shortest paths with a negative cycle should fail with NegativeCycleError")
+		}
+		if len(negative.Cycle) != 2 || negative.Cycle[0]+negative.Cycle[1] != 3 {
+			t.Fatalf(fmt.Sprintf("Negative cycle should be 1 -> 2 -> 1 but is %v", negative.Cycle))
+		}
+	})
+
+	t.Run("Rounding error", func(t *testing.T) {
+		// Fractional capacities next to a large one leave rounding errors on
+		// nodes with no residual arc to take them
+		g := structures.NewGraph(1e12)
+		for i := 0; i < 8; i++ {
+			g.SetNodeByID(i, float64(i), 0, 0, nil)
+		}
+		for _, e := range []struct {
+			from, to int
+			w, c     float64
+		}{
+			{1, 3, 0.3, 1}, {2, 7, 7.7, -1}, {3, 7, 0.3, 1}, {4, 3, 0.3, 0},
+			{5, 3, 1.00000000001e10, -1}, {5, 1, 0.1, 4}, {5, 0, 7.7, 3},
+			{5, 2, 7.7, 2}, {5, 4, 1, 3},
+		} {
+			g.SetEdgeByNodeID(e.from, e.to, e.w, "n", "n", false)
+			g.SetEdgeCostByNodeID(e.from, e.to, e.c, false)
+		}
+
+		for _, source := range []int{0, 5} {
+			done := make(chan *CostFlow)
+			go func() {
+				f, err := CostScaling(g, nil, source, 7)
+				if err != nil {
+					t.Errorf(fmt.Sprintf("Could not find minimum cost flow: %v", err))
+				}
+				done <- f
+			}()
+			select {
+			case f := <-done:
+				// The value is within the precision of the largest capacity
+				max, _ := PushRelabel(g, nil, source, 7)
+				if f == nil || math.Abs(f.Value-max.Value) > 1e-5 {
+					t.Fatalf(fmt.Sprintf("Flow from %d should have value %f", source, max.Value))
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf(fmt.Sprintf("Cost scaling from %d should finish despite rounding error", source))
+			}
+		}
+	})
+
+	t.Run("Fractional costs", func(t *testing.T) {
+		g := cycle(t)
+		g.SetEdgeCostByNodeID(0, 1, 0.5, false)
+		var fractional *IntegralCostError
+		if _, err := CostScaling(g, nil, 0, 2); !errors.As(err, &fractional) {
+			t.Fatalf("Cost scaling with a fractional cost should fail with IntegralCostError")
+		}
+	})
+}
+
+// checkFlowCost checks that f has the wanted cost and that it matches the cost
+// of its edge flows
+func checkFlowCost(t *testing.T, g *structures.Graph, f *CostFlow, cost float64) {
+	t.Helper()
+	c := 0.0
+	for k, flow := range f.EdgeFlow {
+		e, _ := g.GetEdgeByNodeID(k.From, k.To)
+		c += flow * e.Cost
+	}
+	if math.Abs(f.Cost-cost) > 1e-9 || math.Abs(c-cost) > 1e-9 {
+		t.Fatalf(fmt.Sprintf("Flow should cost %f but costs %f", cost, f.Cost))
+	}
+}
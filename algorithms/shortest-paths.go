@@ -0,0 +1,267 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// NoPathError states that the requested target cannot be reached from the
+// source
+type NoPathError struct {
+	from int
+	to   int
+	Err  error
+}
+
+// Error serves the error message for NoPathError
+func (e *NoPathError) Error() string {
+	return fmt.Sprintf("No path from %d to %d: %v", e.from, e.to, e.Err)
+}
+
+func (e *NoPathError) Unwrap() error { return e.Err }
+
+// NegativeWeightError states that an algorithm requiring non-negative edge
+// weights found a negative edge weight
+type NegativeWeightError struct {
+	edge EdgeKey
+	w    float64
+	Err  error
+}
+
+// Error serves the error message for NegativeWeightError
+func (e *NegativeWeightError) Error() string {
+	return fmt.Sprintf(
+		"Negative weight %f on edge from %d to %d: %v",
+		e.w, e.edge.From, e.edge.To, e.Err,
+	)
+}
+
+func (e *NegativeWeightError) Unwrap() error { return e.Err }
+
+// Path is a route through a graph as an ordered list of node IDs along with
+// the total weight of its edges
+type Path struct {
+	Nodes []int   `json:"nodes"`
+	Cost  float64 `json:"cost"`
+}
+
+// Edges returns the keys of the edges along the path
+func (p *Path) Edges() []EdgeKey {
+	if len(p.Nodes) < 2 {
+		return nil
+	}
+	keys := make([]EdgeKey, len(p.Nodes)-1)
+	for i := range keys {
+		keys[i] = EdgeKey{p.Nodes[i], p.Nodes[i+1]}
+	}
+	return keys
+}
+
+// ShortestPaths holds the shortest paths from a source node
+type ShortestPaths struct {
+	Source int
+	// Dist holds the cost of the shortest path to every reached node
+	Dist map[int]float64
+	// Parent holds the previous node on the shortest path to every reached
+	// node other than the source
+	Parent map[int]int
+	// Settled holds node IDs in the order that their costs became final
+	Settled []int
+}
+
+func newShortestPaths(source int) *ShortestPaths {
+	return &ShortestPaths{
+		Source: source,
+		Dist:   map[int]float64{source: 0},
+		Parent: make(map[int]int),
+	}
+}
+
+// PathTo returns the shortest path from the source to the node with the
+// specified ID
+func (p *ShortestPaths) PathTo(id int) (*Path, error) {
+	cost, ok := p.Dist[id]
+	if !ok {
+		return nil, &NoPathError{p.Source, id, nil}
+	}
+
+	nodes := []int{id}
+	for n, ok := p.Parent[id]; ok; n, ok = p.Parent[n] {
+		nodes = append(nodes, n)
+	}
+	reverseInts(nodes)
+	return &Path{nodes, cost}, nil
+}
+
+// Heuristic estimates the cost of the shortest path from n to target. A*
+// never reopens a settled node, so it only finds shortest paths when the
+// heuristic is consistent: the estimate at a node must not exceed the weight
+// of any edge leaving it plus the estimate at the far end of that edge
+type Heuristic func(n, target *structures.Node) float64
+
+// EuclideanDistance returns the straight line distance between two points
+func EuclideanDistance(p1, p2 structures.Point) float64 {
+	dx := p1.X - p2.X
+	dy := p1.Y - p2.Y
+	dz := p1.Z - p2.Z
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// EuclideanHeuristic estimates path costs by the straight line distance
+// between node coordinates. It is only consistent when no edge weight is less
+// than the distance between the coordinates of its nodes
+func EuclideanHeuristic(n, target *structures.Node) float64 {
+	return EuclideanDistance(n.Coords, target.Coords)
+}
+
+// ScaledEuclideanHeuristic returns EuclideanHeuristic scaled by the lowest
+// ratio of edge weight to edge length in g, which keeps it consistent for any
+// non-negative edge weights
+func ScaledEuclideanHeuristic(g *structures.Graph) Heuristic {
+	scale := math.Inf(1)
+	for _, n := range g.Nodes {
+		for _, e := range n.Edges {
+			length := EuclideanDistance(n.Coords, e.Nodes[1].Node.Coords)
+			if length > 0 {
+				scale = math.Min(scale, e.Weight/length)
+			}
+		}
+	}
+	// Without edges of positive length, or with negative weights that the
+	// search rejects, fall back to no estimate at all
+	if math.IsInf(scale, 1) || scale < 0 {
+		scale = 0
+	}
+
+	return func(n, target *structures.Node) float64 {
+		return scale * EuclideanHeuristic(n, target)
+	}
+}
+
+// Dijkstra finds the shortest paths from the source node to every node
+// reachable from it. Edge weights must not be negative
+func Dijkstra(g *structures.Graph, v *Visitor, source int) (*ShortestPaths, error) {
+	s, err := g.GetNodeByID(source)
+	if err != nil {
+		return nil, fmt.Errorf("dijkstra: %w", err)
+	}
+
+	return bestFirstSearch(g, v, s, nil, nil, nil)
+}
+
+// DijkstraPath finds the shortest path from the source node to the target
+// node, stopping as soon as the target is settled. Edge weights must not be
+// negative
+func DijkstraPath(g *structures.Graph, v *Visitor, source, target int) (*Path, *ShortestPaths, error) {
+	return pointToPoint(g, v, source, target, nil)
+}
+
+// AStar finds the shortest path from the source node to the target node,
+// searching nodes in order of their cost from the source plus the estimated
+// cost to the target. If h is nil, ScaledEuclideanHeuristic is used. Edge
+// weights must not be negative
+func AStar(g *structures.Graph, v *Visitor, source, target int, h Heuristic) (*Path, *ShortestPaths, error) {
+	if h == nil {
+		h = ScaledEuclideanHeuristic(g)
+	}
+	return pointToPoint(g, v, source, target, h)
+}
+
+func pointToPoint(g *structures.Graph, v *Visitor, source, target int, h Heuristic) (*Path, *ShortestPaths, error) {
+	s, err := g.GetNodeByID(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("shortest path: %w", err)
+	}
+	t, err := g.GetNodeByID(target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("shortest path: %w", err)
+	}
+
+	sp, err := bestFirstSearch(g, v, s, t, h, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := sp.PathTo(target)
+	if err != nil {
+		return nil, sp, err
+	}
+	return p, sp, nil
+}
+
+// weightFunc returns the weight used for edge e in place of its stored weight
+type weightFunc func(e *structures.Edge) float64
+
+// bestFirstSearch settles nodes from source in order of path cost plus the
+// heuristic estimate to target. If target is nil, the search covers every
+// reachable node and the heuristic is unused. If w is nil, stored edge weights
+// are used
+func bestFirstSearch(g *structures.Graph, v *Visitor, source, target *structures.Node, h Heuristic, w weightFunc) (*ShortestPaths, error) {
+	if w == nil {
+		w = func(e *structures.Edge) float64 { return e.Weight }
+	}
+	estimate := func(n *structures.Node) float64 {
+		if target == nil || h == nil {
+			return 0
+		}
+		return h(n, target)
+	}
+
+	sp := newShortestPaths(source.ID)
+	settled := make(map[int]bool)
+	q := &priorityQueue{}
+	q.push(source.ID, estimate(source))
+	v.discoverNode(source.ID)
+
+	for q.Len() > 0 {
+		id := q.pop().id
+		if settled[id] {
+			continue
+		}
+		settled[id] = true
+		sp.Settled = append(sp.Settled, id)
+		v.examineNode(id)
+		if target != nil && id == target.ID {
+			v.finishNode(id)
+			break
+		}
+
+		n, _ := g.GetNodeByID(id)
+		for _, e := range n.Edges {
+			v.examineEdge(e)
+			ew := w(e)
+			if ew < 0 {
+				return nil, &NegativeWeightError{KeyOf(e), ew, nil}
+			}
+
+			m := e.Nodes[1].Node
+			if settled[m.ID] {
+				continue
+			}
+			d := sp.Dist[id] + ew
+			if old, ok := sp.Dist[m.ID]; !ok || d < old {
+				sp.Dist[m.ID] = d
+				sp.Parent[m.ID] = id
+				q.push(m.ID, d+estimate(m))
+				if !ok {
+					v.discoverNode(m.ID)
+				}
+			}
+		}
+		v.finishNode(id)
+	}
+
+	// Nodes left in the queue of a point-to-point search have tentative
+	// costs, so only settled nodes are reported
+	if target != nil {
+		for id := range sp.Dist {
+			if !settled[id] {
+				delete(sp.Dist, id)
+				delete(sp.Parent, id)
+			}
+		}
+	}
+
+	return sp, nil
+}
@@ -0,0 +1,140 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestShortestPaths(t *testing.T) {
+	log.Printf("Testing shortest paths")
+
+	edges := []mockEdge{
+		{0, 1, 4}, {0, 2, 1}, {2, 1, 2}, {1, 3, 1}, {2, 3, 5}, {3, 4, 3},
+	}
+
+	t.Run("Dijkstra", func(t *testing.T) {
+		g := mockGraph(t, 6, edges)
+		sp, err := Dijkstra(g, nil, 0)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find shortest paths: %v", err))
+		}
+
+		want := map[int]float64{0: 0, 1: 3, 2: 1, 3: 4, 4: 7}
+		checkDistances(t, sp.Dist, want)
+		checkInts(t, "Settled order", sp.Settled, []int{0, 2, 1, 3, 4})
+
+		p, err := sp.PathTo(4)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find path to %d: %v", 4, err))
+		}
+		checkInts(t, "Path to 4", p.Nodes, []int{0, 2, 1, 3, 4})
+		if p.Cost != 7 {
+			t.Fatalf(fmt.Sprintf("Path cost should be %f but is %f", 7.0, p.Cost))
+		}
+
+		var noPath *NoPathError
+		if _, err = sp.PathTo(5); !errors.As(err, &noPath) {
+			t.Fatalf("Path to an unreachable node should fail with NoPathError")
+		}
+	})
+
+	t.Run("Dijkstra point to point", func(t *testing.T) {
+		g := mockGraph(t, 6, edges)
+		p, sp, err := DijkstraPath(g, nil, 0, 1)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find shortest path: %v", err))
+		}
+		checkInts(t, "Path to 1", p.Nodes, []int{0, 2, 1})
+		checkInts(t, "Settled order", sp.Settled, []int{0, 2, 1})
+		if _, ok := sp.Dist[3]; ok {
+			t.Fatalf("Tentative costs should not be reported")
+		}
+
+		var noPath *NoPathError
+		if _, _, err = DijkstraPath(g, nil, 4, 0); !errors.As(err, &noPath) {
+			t.Fatalf("Path to an unreachable node should fail with NoPathError")
+		}
+	})
+
+	t.Run("Negative weights", func(t *testing.T) {
+		g := mockGraph(t, 3, []mockEdge{{0, 1, 1}, {1, 2, -1}})
+		var negative *NegativeWeightError
+		if _, err := Dijkstra(g, nil, 0); !errors.As(err, &negative) {
+			t.Fatalf("Dijkstra should fail with NegativeWeightError")
+		}
+	})
+
+	t.Run("A*", func(t *testing.T) {
+		// A grid of unit spaced nodes with edges weighted by their length
+		size := 10
+		g := structures.NewGraph(100)
+		for i := 0; i < size*size; i++ {
+			g.SetNodeByID(i, float64(i%size), float64(i/size), 0, structures.ColorData{})
+		}
+		for i := 0; i < size*size; i++ {
+			if i%size < size-1 {
+				g.SetEdgeByNodeID(i, i+1, 1, "n", "n", true)
+			}
+			if i/size < size-1 {
+				g.SetEdgeByNodeID(i, i+size, 1, "n", "n", true)
+			}
+		}
+
+		target := size*size - 1
+		dp, dsp, err := DijkstraPath(g, nil, 0, target)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find shortest path: %v", err))
+		}
+		ap, asp, err := AStar(g, nil, 0, target, nil)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find shortest path: %v", err))
+		}
+
+		if math.Abs(ap.Cost-dp.Cost) > 1e-9 || ap.Cost != float64(2*(size-1)) {
+			t.Fatalf(fmt.Sprintf("A* path cost %f should equal Dijkstra path cost %f", ap.Cost, dp.Cost))
+		}
+		if len(ap.Nodes) != 2*size-1 || ap.Nodes[0] != 0 || ap.Nodes[len(ap.Nodes)-1] != target {
+			t.Fatalf(fmt.Sprintf("A* path %v should cross the grid", ap.Nodes))
+		}
+		if len(asp.Settled) >= len(dsp.Settled) {
+			t.Fatalf(
+				fmt.Sprintf("A* should settle fewer nodes than Dijkstra but settled %d and %d",
+					len(asp.Settled), len(dsp.Settled)),
+			)
+		}
+	})
+
+	t.Run("A* with edges lighter than their length", func(t *testing.T) {
+		// Unscaled straight line distances would settle 3 and then 4 through
+		// the heavy edge from 0 before the light path through 1 is explored
+		g := mockGraph(t, 5, []mockEdge{{0, 1, 0.1}, {1, 3, 0.1}, {0, 3, 2}, {3, 4, 1}})
+		dp, _, err := DijkstraPath(g, nil, 0, 4)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find shortest path: %v", err))
+		}
+		ap, _, err := AStar(g, nil, 0, 4, nil)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not find shortest path: %v", err))
+		}
+		if math.Abs(ap.Cost-dp.Cost) > 1e-9 || math.Abs(ap.Cost-1.2) > 1e-9 {
+			t.Fatalf(fmt.Sprintf("A* path cost %f should equal Dijkstra path cost %f", ap.Cost, dp.Cost))
+		}
+	})
+}
+
+func checkDistances(t *testing.T, got, want map[int]float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf(fmt.Sprintf("Distances should be %v but are %v", want, got))
+	}
+	for id, d := range want {
+		if gd, ok := got[id]; !ok || math.Abs(gd-d) > 1e-9 {
+			t.Fatalf(fmt.Sprintf("Distance to %d should be %f but is %f", id, d, gd))
+		}
+	}
+}
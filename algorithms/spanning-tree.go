@@ -0,0 +1,196 @@
+package algorithms
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// SpanningForest is a minimum spanning forest of a graph, which holds a minimum
+// spanning tree of each of its connected components. Spanning forests ignore
+// edge direction, so an edge and its reverse edge are interchangeable
+type SpanningForest struct {
+	// Graph holds every node of the spanned graph and each forest edge in
+	// both directions
+	Graph *structures.Graph
+	// Edges holds the keys of the forest edges in the spanned graph in the
+	// order that they were selected
+	Edges []EdgeKey
+	// Weight is the total weight of the forest edges
+	Weight float64
+}
+
+// newSpanningForest creates a spanning forest holding the nodes of g and no
+// edges
+func newSpanningForest(g *structures.Graph) *SpanningForest {
+	return &SpanningForest{Graph: copyNodes(g)}
+}
+
+// add adds edge e of the spanned graph to the forest
+func (f *SpanningForest) add(v *Visitor, e *structures.Edge) error {
+	err := f.Graph.SetEdgeByNodeID(
+		e.Nodes[0].ID, e.Nodes[1].ID, e.Weight, e.Nodes[0].Tag, e.Nodes[1].Tag, true,
+	)
+	if err != nil {
+		return err
+	}
+
+	f.Edges = append(f.Edges, KeyOf(e))
+	f.Weight += e.Weight
+	v.selectEdge(e)
+	return nil
+}
+
+// lighterEdge orders edges by weight, breaking ties by their undirected keys.
+// Borůvka relies on the order being consistent to avoid selecting cycles
+func lighterEdge(a, b *structures.Edge) bool {
+	if a.Weight != b.Weight {
+		return a.Weight < b.Weight
+	}
+	ka, kb := undirectedKey(a), undirectedKey(b)
+	if ka.From != kb.From {
+		return ka.From < kb.From
+	}
+	return ka.To < kb.To
+}
+
+// otherNode returns the node at the opposite end of e from the node with the
+// specified ID
+func otherNode(e *structures.Edge, id int) *structures.Node {
+	if e.Nodes[0].ID == id {
+		return e.Nodes[1].Node
+	}
+	return e.Nodes[0].Node
+}
+
+// Prim finds a minimum spanning forest of g by growing a tree from a node of
+// each connected component, adding the lightest edge from the tree to a node
+// outside of it until the component is spanned
+func Prim(g *structures.Graph, v *Visitor) (*SpanningForest, error) {
+	f := newSpanningForest(g)
+	reached := make(map[int]bool)
+	inTree := make(map[int]bool)
+
+	var (
+		candidates []*structures.Edge
+		q          priorityQueue
+	)
+	// grow adds node n to the tree and queues the edges leaving the tree
+	// through it
+	grow := func(n *structures.Node) {
+		inTree[n.ID] = true
+		v.examineNode(n.ID)
+		for _, edges := range [][]*structures.Edge{g.OutEdges(n), g.InEdges(n)} {
+			for _, e := range edges {
+				v.examineEdge(e)
+				m := otherNode(e, n.ID)
+				if inTree[m.ID] {
+					continue
+				}
+				if !reached[m.ID] {
+					reached[m.ID] = true
+					v.discoverNode(m.ID)
+				}
+				candidates = append(candidates, e)
+				q.push(len(candidates)-1, e.Weight)
+			}
+		}
+		v.finishNode(n.ID)
+	}
+
+	for _, root := range g.Nodes {
+		if inTree[root.ID] {
+			continue
+		}
+
+		reached[root.ID] = true
+		v.discoverNode(root.ID)
+		grow(root)
+		for q.Len() > 0 {
+			e := candidates[q.pop().id]
+			m := e.Nodes[1].Node
+			if inTree[m.ID] {
+				m = e.Nodes[0].Node
+				if inTree[m.ID] {
+					continue
+				}
+			}
+
+			if err := f.add(v, e); err != nil {
+				return nil, fmt.Errorf("prim: %w", err)
+			}
+			grow(m)
+		}
+	}
+
+	return f, nil
+}
+
+// Kruskal finds a minimum spanning forest of g by considering edges from
+// lightest to heaviest, adding each edge that joins two trees of the forest
+func Kruskal(g *structures.Graph, v *Visitor) (*SpanningForest, error) {
+	var edges []*structures.Edge
+	for _, n := range g.Nodes {
+		edges = append(edges, n.Edges...)
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		return lighterEdge(edges[i], edges[j])
+	})
+
+	f := newSpanningForest(g)
+	trees := newUnionFind()
+	for _, e := range edges {
+		v.examineEdge(e)
+		if !trees.union(e.Nodes[0].ID, e.Nodes[1].ID) {
+			continue
+		}
+		if err := f.add(v, e); err != nil {
+			return nil, fmt.Errorf("kruskal: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+// Boruvka finds a minimum spanning forest of g in rounds. Each round adds the
+// lightest edge leaving every tree of the forest, at least halving the number
+// of trees, until no edge joins two trees
+func Boruvka(g *structures.Graph, v *Visitor) (*SpanningForest, error) {
+	f := newSpanningForest(g)
+	trees := newUnionFind()
+	for {
+		// lightest maps the representative node of each tree to the lightest
+		// edge leaving the tree
+		lightest := make(map[int]*structures.Edge)
+		for _, n := range g.Nodes {
+			for _, e := range n.Edges {
+				v.examineEdge(e)
+				a, b := trees.find(e.Nodes[0].ID), trees.find(e.Nodes[1].ID)
+				if a == b {
+					continue
+				}
+				for _, t := range []int{a, b} {
+					if l, ok := lightest[t]; !ok || lighterEdge(e, l) {
+						lightest[t] = e
+					}
+				}
+			}
+		}
+		if len(lightest) == 0 {
+			return f, nil
+		}
+
+		// Two trees may select the same edge, so edges are only added if
+		// their trees have not been joined earlier in the round
+		for _, n := range g.Nodes {
+			e, ok := lightest[n.ID]
+			if !ok || !trees.union(e.Nodes[0].ID, e.Nodes[1].ID) {
+				continue
+			}
+			if err := f.add(v, e); err != nil {
+				return nil, fmt.Errorf("boruvka: %w", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package algorithms
+
+import (
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestSpanningForest(t *testing.T) {
+	log.Printf("Testing minimum spanning forests")
+
+	// Nodes 0 through 6 form a component with a minimum spanning tree of
+	// weight 39, nodes 7 and 8 form another and node 9 is isolated
+	edges := []mockEdge{
+		{0, 1, 7}, {0, 3, 5}, {1, 2, 8}, {1, 3, 9}, {1, 4, 7}, {2, 4, 5},
+		{3, 4, 15}, {3, 5, 6}, {4, 5, 8}, {4, 6, 9}, {5, 6, 11}, {7, 8, 2},
+	}
+
+	algs := map[string]func(*structures.Graph, *Visitor) (*SpanningForest, error){
+		"Prim":    Prim,
+		"Kruskal": Kruskal,
+		"Boruvka": Boruvka,
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			g := mockUndirectedGraph(t, 10, edges)
+			var selected []EdgeKey
+			v := &Visitor{SelectEdge: func(e *structures.Edge) {
+				selected = append(selected, KeyOf(e))
+			}}
+			f, err := alg(g, v)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find spanning forest: %v", err))
+			}
+			checkSpanningForest(t, g, f, 41, 3)
+			if len(selected) != len(f.Edges) {
+				t.Fatalf(fmt.Sprintf("Visitor should select %d edges but selected %d", len(f.Edges), len(selected)))
+			}
+
+			// Direction is ignored
+			g = mockGraph(t, 10, edges)
+			f, err = alg(g, nil)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find spanning forest: %v", err))
+			}
+			checkSpanningForest(t, g, f, 41, 3)
+		})
+	}
+
+	t.Run("Kruskal order", func(t *testing.T) {
+		g := mockUndirectedGraph(t, 10, edges)
+		f, _ := Kruskal(g, nil)
+		want := []EdgeKey{{7, 8}, {0, 3}, {2, 4}, {3, 5}, {0, 1}, {1, 4}, {4, 6}}
+		for i := range want {
+			if f.Edges[i] != want[i] {
+				t.Fatalf(fmt.Sprintf("Edges should be selected in order %v but are %v", want, f.Edges))
+			}
+		}
+	})
+}
+
+// checkSpanningForest checks that f is a spanning forest of g with the
+// requested weight and number of trees
+func checkSpanningForest(t *testing.T, g *structures.Graph, f *SpanningForest, weight float64, trees int) {
+	t.Helper()
+	if f.Weight != weight {
+		t.Fatalf(fmt.Sprintf("Forest should weigh %f but weighs %f", weight, f.Weight))
+	}
+	if len(f.Graph.Nodes) != len(g.Nodes) {
+		t.Fatalf(fmt.Sprintf("Forest should hold %d nodes but holds %d", len(g.Nodes), len(f.Graph.Nodes)))
+	}
+	if len(f.Edges) != len(g.Nodes)-trees || f.Graph.NumEdges != 2*len(f.Edges) {
+		t.Fatalf(fmt.Sprintf("Forest of %d trees should hold %d edges but holds %d", trees, len(g.Nodes)-trees, len(f.Edges)))
+	}
+
+	w := 0.0
+	for _, k := range f.Edges {
+		e, err := g.GetEdgeByNodeID(k.From, k.To)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Forest edge %v should be an edge of the graph", k))
+		}
+		if _, err = f.Graph.GetEdgeByNodeID(k.To, k.From); err != nil {
+			t.Fatalf(fmt.Sprintf("Forest should hold both directions of edge %v", k))
+		}
+		w += e.Weight
+	}
+	if w != weight {
+		t.Fatalf(fmt.Sprintf("Forest edges should weigh %f but weigh %f", weight, w))
+	}
+
+	// A forest with the right number of edges is acyclic if it has the right
+	// number of trees
+	tr, _ := BreadthFirstSearch(f.Graph, nil)
+	if len(tr.Roots) != trees {
+		t.Fatalf(fmt.Sprintf("Forest should have %d trees but has %d", trees, len(tr.Roots)))
+	}
+}
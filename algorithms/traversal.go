@@ -0,0 +1,288 @@
+package algorithms
+
+import (
+	"fmt"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+// EdgeClass classifies an edge by its role in a traversal
+type EdgeClass int
+
+const (
+	// TreeEdge leads to a node that is discovered through it
+	TreeEdge EdgeClass = iota
+	// BackEdge leads to an ancestor of its near node in the traversal tree.
+	// Self loops are back edges
+	BackEdge
+	// ForwardEdge leads to a descendant of its near node that was discovered
+	// through another edge
+	ForwardEdge
+	// CrossEdge leads to a node that is neither an ancestor nor a descendant
+	CrossEdge
+)
+
+// String returns the name of the edge class
+func (c EdgeClass) String() string {
+	switch c {
+	case TreeEdge:
+		return "tree"
+	case BackEdge:
+		return "back"
+	case ForwardEdge:
+		return "forward"
+	case CrossEdge:
+		return "cross"
+	}
+	return fmt.Sprintf("EdgeClass(%d)", int(c))
+}
+
+// Traversal holds the result of a breadth-first or depth-first traversal
+type Traversal struct {
+	// Order holds node IDs in the order that they were discovered
+	Order []int
+	// Discovery and Finish hold the time at which each node was discovered and
+	// finished. The clock is shared by both and ticks once per event
+	Discovery map[int]int
+	Finish    map[int]int
+	// Parent holds the parent of each node in the traversal forest. Roots have
+	// no entry
+	Parent map[int]int
+	// Roots holds the node each tree of the traversal forest was started from
+	Roots []int
+	// Depth holds the number of tree edges from the root of each node's tree
+	Depth map[int]int
+	// Edges holds the classification of every edge examined by the traversal
+	Edges map[EdgeKey]EdgeClass
+}
+
+func newTraversal() *Traversal {
+	return &Traversal{
+		Discovery: make(map[int]int),
+		Finish:    make(map[int]int),
+		Parent:    make(map[int]int),
+		Depth:     make(map[int]int),
+		Edges:     make(map[EdgeKey]EdgeClass),
+	}
+}
+
+// Visited returns whether the traversal reached the node with the specified ID
+func (t *Traversal) Visited(id int) bool {
+	_, ok := t.Discovery[id]
+	return ok
+}
+
+// PathTo returns the node IDs on the tree path from the root of id's tree to
+// id, or nil if id was not visited
+func (t *Traversal) PathTo(id int) []int {
+	if !t.Visited(id) {
+		return nil
+	}
+
+	path := []int{id}
+	for p, ok := t.Parent[id]; ok; p, ok = t.Parent[p] {
+		path = append(path, p)
+	}
+	reverseInts(path)
+	return path
+}
+
+// BreadthFirstSearch traverses g in breadth-first order following edge
+// direction, starting from each of the source node IDs in turn. If no sources
+// are given, every node of g is used as a source so that the whole graph is
+// traversed. Depth holds the hop distance from the root of each node's tree
+func BreadthFirstSearch(g *structures.Graph, v *Visitor, sources ...int) (*Traversal, error) {
+	roots, err := sourceNodes(g, sources)
+	if err != nil {
+		return nil, fmt.Errorf("breadth-first search: %w", err)
+	}
+
+	t := newTraversal()
+	clock := 0
+	var nonTree []EdgeKey
+	for _, root := range roots {
+		if t.Visited(root.ID) {
+			continue
+		}
+
+		t.Roots = append(t.Roots, root.ID)
+		clock++
+		t.Discovery[root.ID] = clock
+		t.Order = append(t.Order, root.ID)
+		v.discoverNode(root.ID)
+
+		queue := []*structures.Node{root}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			v.examineNode(n.ID)
+
+			for _, e := range n.Edges {
+				v.examineEdge(e)
+				m := e.Nodes[1].Node
+				if t.Visited(m.ID) {
+					nonTree = append(nonTree, KeyOf(e))
+					continue
+				}
+
+				t.Edges[KeyOf(e)] = TreeEdge
+				t.Parent[m.ID] = n.ID
+				t.Depth[m.ID] = t.Depth[n.ID] + 1
+				clock++
+				t.Discovery[m.ID] = clock
+				t.Order = append(t.Order, m.ID)
+				v.discoverNode(m.ID)
+				queue = append(queue, m)
+			}
+
+			clock++
+			t.Finish[n.ID] = clock
+			v.finishNode(n.ID)
+		}
+	}
+
+	// Discovery and finish times of a breadth-first search do not nest, so
+	// non-tree edges are classified against the intervals of the search tree
+	pre, post := treeIntervals(t)
+	for _, k := range nonTree {
+		t.Edges[k] = classifyByIntervals(k, pre, post)
+	}
+
+	return t, nil
+}
+
+// dfsFrame is a node on the depth-first search stack along with the position of
+// the next edge to examine
+type dfsFrame struct {
+	n    *structures.Node
+	next int
+}
+
+// DepthFirstSearch traverses g in depth-first order following edge direction,
+// starting from each of the source node IDs in turn. If no sources are given,
+// every node of g is used as a source so that the whole graph is traversed
+func DepthFirstSearch(g *structures.Graph, v *Visitor, sources ...int) (*Traversal, error) {
+	roots, err := sourceNodes(g, sources)
+	if err != nil {
+		return nil, fmt.Errorf("depth-first search: %w", err)
+	}
+
+	t := newTraversal()
+	clock := 0
+	for _, root := range roots {
+		if t.Visited(root.ID) {
+			continue
+		}
+
+		t.Roots = append(t.Roots, root.ID)
+		clock++
+		t.Discovery[root.ID] = clock
+		t.Order = append(t.Order, root.ID)
+		v.discoverNode(root.ID)
+		v.examineNode(root.ID)
+
+		// The stack is iterated rather than recursed so that deep graphs do
+		// not grow the call stack
+		stack := []dfsFrame{{root, 0}}
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			n := f.n
+			if f.next == len(n.Edges) {
+				clock++
+				t.Finish[n.ID] = clock
+				v.finishNode(n.ID)
+				stack = stack[:len(stack)-1]
+				if len(stack) > 0 {
+					v.examineNode(stack[len(stack)-1].n.ID)
+				}
+				continue
+			}
+
+			e := n.Edges[f.next]
+			f.next++
+			v.examineEdge(e)
+			m := e.Nodes[1].Node
+			k := KeyOf(e)
+			if !t.Visited(m.ID) {
+				t.Edges[k] = TreeEdge
+				t.Parent[m.ID] = n.ID
+				t.Depth[m.ID] = t.Depth[n.ID] + 1
+				clock++
+				t.Discovery[m.ID] = clock
+				t.Order = append(t.Order, m.ID)
+				v.discoverNode(m.ID)
+				v.examineNode(m.ID)
+				stack = append(stack, dfsFrame{m, 0})
+			} else if _, finished := t.Finish[m.ID]; !finished {
+				t.Edges[k] = BackEdge
+			} else if t.Discovery[n.ID] < t.Discovery[m.ID] {
+				t.Edges[k] = ForwardEdge
+			} else {
+				t.Edges[k] = CrossEdge
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// treeFrame is a node of a traversal tree on a stack along with the position of
+// the next child to visit
+type treeFrame struct {
+	id   int
+	next int
+}
+
+// treeIntervals returns the preorder and postorder times of every node in the
+// traversal forest of t
+func treeIntervals(t *Traversal) (map[int]int, map[int]int) {
+	children := make(map[int][]int)
+	for _, id := range t.Order {
+		if p, ok := t.Parent[id]; ok {
+			children[p] = append(children[p], id)
+		}
+	}
+
+	pre := make(map[int]int, len(t.Order))
+	post := make(map[int]int, len(t.Order))
+	clock := 0
+	for _, root := range t.Roots {
+		clock++
+		pre[root] = clock
+		stack := []treeFrame{{root, 0}}
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			kids := children[f.id]
+			if f.next == len(kids) {
+				clock++
+				post[f.id] = clock
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			c := kids[f.next]
+			f.next++
+			clock++
+			pre[c] = clock
+			stack = append(stack, treeFrame{c, 0})
+		}
+	}
+	return pre, post
+}
+
+// classifyByIntervals classifies a non-tree edge using the preorder and
+// postorder times of its nodes in a traversal forest
+func classifyByIntervals(k EdgeKey, pre, post map[int]int) EdgeClass {
+	if pre[k.To] <= pre[k.From] && post[k.From] <= post[k.To] {
+		return BackEdge
+	} else if pre[k.From] < pre[k.To] && post[k.To] < post[k.From] {
+		return ForwardEdge
+	}
+	return CrossEdge
+}
+
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
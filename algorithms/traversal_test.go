@@ -0,0 +1,109 @@
+package algorithms
+
+import (
+	"fmt"
+	"log"
+	"testing"
+)
+
+func TestTraversal(t *testing.T) {
+	log.Printf("Testing traversals")
+
+	// 0 -> 1 -> 2 -> 0 forms a cycle, 0 -> 2 skips ahead and 3 -> 1 crosses
+	// in from a separate tree
+	edges := []mockEdge{
+		{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {0, 2, 1}, {3, 1, 1}, {2, 4, 1},
+	}
+
+	t.Run("Breadth-first search", func(t *testing.T) {
+		g := mockGraph(t, 5, edges)
+		var examined []int
+		v := &Visitor{ExamineNode: func(id int) { examined = append(examined, id) }}
+		tr, err := BreadthFirstSearch(g, v)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not traverse graph: %v", err))
+		}
+
+		checkInts(t, "Visit order", tr.Order, []int{0, 1, 2, 4, 3})
+		checkInts(t, "Examined nodes", examined, tr.Order)
+		checkInts(t, "Roots", tr.Roots, []int{0, 3})
+		checkInts(t, "Path to 4", tr.PathTo(4), []int{0, 2, 4})
+		if tr.Depth[4] != 2 || tr.Depth[3] != 0 {
+			t.Fatalf("Breadth-first depths should be hop distances from the root")
+		}
+		checkEdgeClasses(t, tr, map[EdgeKey]EdgeClass{
+			{0, 1}: TreeEdge,
+			{0, 2}: TreeEdge,
+			{2, 4}: TreeEdge,
+			{1, 2}: CrossEdge,
+			{2, 0}: BackEdge,
+			{3, 1}: CrossEdge,
+		})
+		checkTimes(t, tr)
+	})
+
+	t.Run("Depth-first search", func(t *testing.T) {
+		g := mockGraph(t, 5, edges)
+		tr, err := DepthFirstSearch(g, nil)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not traverse graph: %v", err))
+		}
+
+		checkInts(t, "Visit order", tr.Order, []int{0, 1, 2, 4, 3})
+		checkInts(t, "Path to 4", tr.PathTo(4), []int{0, 1, 2, 4})
+		checkEdgeClasses(t, tr, map[EdgeKey]EdgeClass{
+			{0, 1}: TreeEdge,
+			{1, 2}: TreeEdge,
+			{2, 4}: TreeEdge,
+			{2, 0}: BackEdge,
+			{0, 2}: ForwardEdge,
+			{3, 1}: CrossEdge,
+		})
+		checkTimes(t, tr)
+
+		// Nested discovery and finish times are the parenthesis property
+		for child, parent := range tr.Parent {
+			if tr.Discovery[parent] >= tr.Discovery[child] || tr.Finish[child] >= tr.Finish[parent] {
+				t.Fatalf(fmt.Sprintf("Interval of %d should nest within interval of %d", child, parent))
+			}
+		}
+	})
+
+	t.Run("Traversal from sources", func(t *testing.T) {
+		g := mockGraph(t, 5, edges)
+		tr, err := DepthFirstSearch(g, nil, 3)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not traverse graph: %v", err))
+		}
+		checkInts(t, "Visit order", tr.Order, []int{3, 1, 2, 0, 4})
+		if tr.Edges[EdgeKey{0, 2}] != BackEdge {
+			t.Fatalf("Edge from 0 to 2 should be a back edge when starting from 3")
+		}
+
+		_, err = BreadthFirstSearch(g, nil, 42)
+		if err == nil {
+			t.Fatalf("Traversal from a missing node should fail")
+		}
+	})
+}
+
+func checkEdgeClasses(t *testing.T, tr *Traversal, want map[EdgeKey]EdgeClass) {
+	t.Helper()
+	if len(tr.Edges) != len(want) {
+		t.Fatalf(fmt.Sprintf("Expected %d classified edges but got %d", len(want), len(tr.Edges)))
+	}
+	for k, c := range want {
+		if tr.Edges[k] != c {
+			t.Fatalf(fmt.Sprintf("Edge %v should be a %s edge but is a %s edge", k, c, tr.Edges[k]))
+		}
+	}
+}
+
+func checkTimes(t *testing.T, tr *Traversal) {
+	t.Helper()
+	for _, id := range tr.Order {
+		if tr.Discovery[id] >= tr.Finish[id] {
+			t.Fatalf(fmt.Sprintf("Node %d should be discovered before it is finished", id))
+		}
+	}
+}
@@ -0,0 +1,479 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+const (
+	// HeldKarpMaxNodes is the most nodes for which HeldKarp builds its table,
+	// which grows with the number of subsets of nodes
+	HeldKarpMaxNodes = 16
+	// tourEpsilon is the least length by which a tour must shorten for a move
+	// to count as an improvement, so that rounding errors do not loop forever
+	tourEpsilon = 1e-9
+	// orOptMaxSegment is the most consecutive nodes moved at once by OrOpt
+	orOptMaxSegment = 3
+)
+
+// TourSizeError states that a graph has too many nodes for an exact tour
+type TourSizeError struct {
+	numNodes int
+	Err      error
+}
+
+// Error serves the error message for TourSizeError
+func (e *TourSizeError) Error() string {
+	return fmt.Sprintf("Exact tour of %d nodes exceeds the limit of %d: %v", e.numNodes, HeldKarpMaxNodes, e.Err)
+}
+
+func (e *TourSizeError) Unwrap() error { return e.Err }
+
+// TourError states that a tour does not visit a node of the graph exactly once
+type TourError struct {
+	id  int
+	Err error
+}
+
+// Error serves the error message for TourError
+func (e *TourError) Error() string {
+	return fmt.Sprintf("Tour does not visit node %d exactly once: %v", e.id, e.Err)
+}
+
+func (e *TourError) Unwrap() error { return e.Err }
+
+// Tour is a closed walk that visits every node of a graph once
+type Tour struct {
+	// Nodes holds the node IDs in visiting order. The tour returns from the
+	// last node to the first, which is not repeated
+	Nodes []int
+	// Length is the total distance around the tour
+	Length float64
+}
+
+// tourDistances returns the distance between each pair of node positions of g
+// viewed as undirected. Nodes joined by an edge are as far apart as the
+// lightest edge between them, and other nodes are as far apart as their
+// coordinates
+func tourDistances(g *structures.Graph) [][]float64 {
+	n := len(g.Nodes)
+	index := make(map[int]int, n)
+	d := make([][]float64, n)
+	joined := make([][]bool, n)
+	for i, a := range g.Nodes {
+		index[a.ID] = i
+		d[i] = make([]float64, n)
+		joined[i] = make([]bool, n)
+		for j, b := range g.Nodes {
+			d[i][j] = EuclideanDistance(a.Coords, b.Coords)
+		}
+	}
+	for i, a := range g.Nodes {
+		for _, e := range a.Edges {
+			j := index[e.Nodes[1].ID]
+			if i != j && (!joined[i][j] || e.Weight < d[i][j]) {
+				d[i][j], d[j][i] = e.Weight, e.Weight
+				joined[i][j], joined[j][i] = true, true
+			}
+		}
+	}
+	return d
+}
+
+// tourLength returns the length of the tour through node positions t
+func tourLength(d [][]float64, t []int) float64 {
+	length := 0.0
+	for i := range t {
+		length += d[t[i]][t[(i+1)%len(t)]]
+	}
+	return length
+}
+
+// newTour creates the tour of g through node positions t
+func newTour(g *structures.Graph, d [][]float64, t []int) *Tour {
+	tour := &Tour{Nodes: make([]int, len(t)), Length: tourLength(d, t)}
+	for i, p := range t {
+		tour.Nodes[i] = g.Nodes[p].ID
+	}
+	return tour
+}
+
+// reportTour reports the tour of g through node positions t to v
+func reportTour(g *structures.Graph, v *Visitor, t []int) {
+	if v == nil || v.UpdateTour == nil {
+		return
+	}
+	ids := make([]int, len(t))
+	for i, p := range t {
+		ids[i] = g.Nodes[p].ID
+	}
+	v.updateTour(ids)
+}
+
+// tourPositions returns the node positions of g visited by the tour of node
+// IDs, or the positions in order if the tour is empty. If the tour does not
+// visit every node exactly once, a *TourError is returned
+func tourPositions(g *structures.Graph, tour []int) ([]int, error) {
+	if len(tour) == 0 {
+		t := make([]int, len(g.Nodes))
+		for i := range t {
+			t[i] = i
+		}
+		return t, nil
+	}
+
+	index := make(map[int]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n.ID] = i
+	}
+	seen := make([]bool, len(g.Nodes))
+	t := make([]int, len(tour))
+	for i, id := range tour {
+		p, ok := index[id]
+		if !ok {
+			_, err := g.GetNodeByID(id)
+			return nil, &TourError{id, err}
+		}
+		if seen[p] {
+			return nil, &TourError{id, nil}
+		}
+		seen[p] = true
+		t[i] = p
+	}
+	for p, ok := range seen {
+		if !ok {
+			return nil, &TourError{g.Nodes[p].ID, nil}
+		}
+	}
+	return t, nil
+}
+
+// HeldKarp finds a shortest tour of g viewed as undirected with the dynamic
+// program of Held and Karp, which finds the shortest path from the first node
+// through each subset of the other nodes to each node of the subset. Nodes not
+// joined by an edge are as far apart as their coordinates. The table grows
+// exponentially, so a graph of more than HeldKarpMaxNodes nodes returns a
+// *TourSizeError
+func HeldKarp(g *structures.Graph, v *Visitor) (*Tour, error) {
+	n := len(g.Nodes)
+	if n > HeldKarpMaxNodes {
+		return nil, fmt.Errorf("held-karp: %w", &TourSizeError{n, nil})
+	}
+	d := tourDistances(g)
+	if n <= 2 {
+		t, _ := tourPositions(g, nil)
+		reportTour(g, v, t)
+		return newTour(g, d, t), nil
+	}
+
+	// cost[s][j] is the length of the shortest path from node 0 through the
+	// nodes of subset s of the other nodes, ending at node j+1 in s. Node j+1
+	// is bit j of s
+	m := n - 1
+	cost := make([][]float64, 1<<m)
+	via := make([][]int, 1<<m)
+	for s := range cost {
+		cost[s] = make([]float64, m)
+		via[s] = make([]int, m)
+		for j := range cost[s] {
+			cost[s][j] = math.Inf(1)
+		}
+	}
+	for j := 0; j < m; j++ {
+		cost[1<<j][j] = d[0][j+1]
+		via[1<<j][j] = -1
+	}
+	for s := 1; s < 1<<m; s++ {
+		for j := 0; j < m; j++ {
+			if s&(1<<j) == 0 || math.IsInf(cost[s][j], 1) {
+				continue
+			}
+			for k := 0; k < m; k++ {
+				if s&(1<<k) != 0 {
+					continue
+				}
+				next := s | 1<<k
+				if c := cost[s][j] + d[j+1][k+1]; c < cost[next][k] {
+					cost[next][k] = c
+					via[next][k] = j
+				}
+			}
+		}
+	}
+
+	full := 1<<m - 1
+	last := 0
+	for j := 1; j < m; j++ {
+		if cost[full][j]+d[j+1][0] < cost[full][last]+d[last+1][0] {
+			last = j
+		}
+	}
+	t := make([]int, 0, n)
+	for s, j := full, last; j != -1; {
+		t = append(t, j+1)
+		s, j = s&^(1<<j), via[s][j]
+	}
+	t = append(t, 0)
+	reverseInts(t)
+
+	reportTour(g, v, t)
+	return newTour(g, d, t), nil
+}
+
+// NearestNeighborTour builds a tour of g viewed as undirected from the start
+// node by always moving to the nearest node not yet visited. Nodes not joined
+// by an edge are as far apart as their coordinates
+func NearestNeighborTour(g *structures.Graph, v *Visitor, start int) (*Tour, error) {
+	n, err := g.GetNodeByID(start)
+	if err != nil {
+		return nil, fmt.Errorf("nearest neighbor: %w", err)
+	}
+	d := tourDistances(g)
+	t := []int{indexOf(nodeIDs(g), n.ID)}
+	visited := make([]bool, len(g.Nodes))
+	visited[t[0]] = true
+	v.examineNode(start)
+	for len(t) < len(g.Nodes) {
+		last, next := t[len(t)-1], -1
+		for p := range g.Nodes {
+			if !visited[p] && (next == -1 || d[last][p] < d[last][next]) {
+				next = p
+			}
+		}
+		visited[next] = true
+		t = append(t, next)
+		v.examineNode(g.Nodes[next].ID)
+	}
+
+	reportTour(g, v, t)
+	return newTour(g, d, t), nil
+}
+
+// nodeIDs returns the IDs of the nodes of g in order
+func nodeIDs(g *structures.Graph) []int {
+	ids := make([]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[i] = n.ID
+	}
+	return ids
+}
+
+// Christofides builds a tour of g viewed as undirected with the algorithm of
+// Christofides. It joins a minimum spanning tree to a minimum weight perfect
+// matching of the odd degree nodes of the tree, follows an Eulerian circuit of
+// the result and skips nodes already visited. Nodes not joined by an edge are
+// as far apart as their coordinates. When distances obey the triangle
+// inequality the tour is at most half again as long as the shortest
+func Christofides(g *structures.Graph, v *Visitor) *Tour {
+	n := len(g.Nodes)
+	d := tourDistances(g)
+	if n <= 2 {
+		t, _ := tourPositions(g, nil)
+		reportTour(g, v, t)
+		return newTour(g, d, t)
+	}
+
+	// Prim's algorithm over the distances between every pair of nodes
+	var ends [][2]int
+	inTree := make([]bool, n)
+	parent := make([]int, n)
+	best := make([]float64, n)
+	for p := range best {
+		best[p] = math.Inf(1)
+	}
+	best[0] = 0
+	parent[0] = -1
+	degree := make([]int, n)
+	for range g.Nodes {
+		next := -1
+		for p := range g.Nodes {
+			if !inTree[p] && (next == -1 || best[p] < best[next]) {
+				next = p
+			}
+		}
+		inTree[next] = true
+		if parent[next] != -1 {
+			ends = append(ends, [2]int{parent[next], next})
+			degree[parent[next]]++
+			degree[next]++
+		}
+		for p := range g.Nodes {
+			if !inTree[p] && d[next][p] < best[p] {
+				best[p], parent[p] = d[next][p], next
+			}
+		}
+	}
+
+	var odd []int
+	for p := range g.Nodes {
+		if degree[p]%2 == 1 {
+			odd = append(odd, p)
+		}
+	}
+	ends = append(ends, minWeightPerfectMatching(odd, d)...)
+
+	visited := make([]bool, n)
+	t := make([]int, 0, n)
+	for _, p := range eulerWalk(n, ends, false, 0, nil) {
+		if !visited[p] {
+			visited[p] = true
+			t = append(t, p)
+		}
+	}
+
+	reportTour(g, v, t)
+	return newTour(g, d, t)
+}
+
+// TwoOpt shortens a tour of g viewed as undirected by 2-opt moves, each of
+// which replaces two edges of the tour by reversing the part between them,
+// until no move shortens it. The tour holds node IDs in visiting order, and
+// an empty tour starts from the nodes in order. If the tour does not visit
+// every node exactly once, a *TourError is returned. Nodes not joined by an
+// edge are as far apart as their coordinates
+func TwoOpt(g *structures.Graph, v *Visitor, tour []int) (*Tour, error) {
+	t, err := tourPositions(g, tour)
+	if err != nil {
+		return nil, fmt.Errorf("2-opt: %w", err)
+	}
+	d := tourDistances(g)
+	reportTour(g, v, t)
+	for twoOptPass(d, t) {
+		reportTour(g, v, t)
+	}
+	return newTour(g, d, t), nil
+}
+
+// twoOptPass makes the first 2-opt move that shortens the tour through node
+// positions t, and returns whether there was one
+func twoOptPass(d [][]float64, t []int) bool {
+	n := len(t)
+	for i := 0; i < n-2; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			a, b, c, e := t[i], t[i+1], t[j], t[(j+1)%n]
+			if d[a][c]+d[b][e] < d[a][b]+d[c][e]-tourEpsilon {
+				reverseInts(t[i+1 : j+1])
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OrOpt shortens a tour of g viewed as undirected by Or-opt moves, each of
+// which moves up to three consecutive nodes of the tour, possibly reversed, to
+// another place in it, until no move shortens it. The tour holds node IDs in
+// visiting order, and an empty tour starts from the nodes in order. If the
+// tour does not visit every node exactly once, a *TourError is returned. Nodes
+// not joined by an edge are as far apart as their coordinates
+func OrOpt(g *structures.Graph, v *Visitor, tour []int) (*Tour, error) {
+	t, err := tourPositions(g, tour)
+	if err != nil {
+		return nil, fmt.Errorf("or-opt: %w", err)
+	}
+	d := tourDistances(g)
+	reportTour(g, v, t)
+	for {
+		moved := orOptPass(d, t)
+		if moved == nil {
+			break
+		}
+		t = moved
+		reportTour(g, v, t)
+	}
+	return newTour(g, d, t), nil
+}
+
+// orOptPass makes the first Or-opt move that shortens the tour through node
+// positions t, and returns the new tour or nil if there was none
+func orOptPass(d [][]float64, t []int) []int {
+	n := len(t)
+	for length := 1; length <= orOptMaxSegment && length+2 < n; length++ {
+		for i := 0; i < n; i++ {
+			// Rotate the tour so that the segment comes first and the rest
+			// of the tour follows as a path between its ends
+			rotated := append(append([]int{}, t[i:]...), t[:i]...)
+			segment, rest := rotated[:length], rotated[length:]
+			first, last := segment[0], segment[length-1]
+			saved := d[rest[len(rest)-1]][first] + d[last][rest[0]] - d[rest[len(rest)-1]][rest[0]]
+
+			for k := 0; k+1 < len(rest); k++ {
+				a, b := rest[k], rest[k+1]
+				forward := d[a][first] + d[last][b] - d[a][b]
+				backward := d[a][last] + d[first][b] - d[a][b]
+				if math.Min(forward, backward) >= saved-tourEpsilon {
+					continue
+				}
+
+				moved := make([]int, 0, n)
+				moved = append(moved, rest[:k+1]...)
+				if backward < forward {
+					for s := length - 1; s >= 0; s-- {
+						moved = append(moved, segment[s])
+					}
+				} else {
+					moved = append(moved, segment...)
+				}
+				return append(moved, rest[k+1:]...)
+			}
+		}
+	}
+	return nil
+}
+
+// SimulatedAnnealing shortens a tour of g viewed as undirected by simulated
+// annealing over random 2-opt moves. A move that lengthens the tour by delta
+// is still taken with probability exp(-delta/T), where the temperature T
+// starts at the average distance between consecutive nodes of the tour and
+// cools geometrically to a thousandth of that over the iterations. The
+// shortest tour seen is returned. The tour holds node IDs in visiting order,
+// and an empty tour starts from the nodes in order. If the tour does not visit
+// every node exactly once, a *TourError is returned. seed seeds the random
+// moves
+func SimulatedAnnealing(g *structures.Graph, v *Visitor, tour []int, iterations int, seed int64) (*Tour, error) {
+	t, err := tourPositions(g, tour)
+	if err != nil {
+		return nil, fmt.Errorf("simulated annealing: %w", err)
+	}
+	d := tourDistances(g)
+	n := len(t)
+	best := append([]int{}, t...)
+	length := tourLength(d, t)
+	bestLength := length
+	reportTour(g, v, best)
+	if n < 4 || iterations <= 0 {
+		return newTour(g, d, best), nil
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	temperature := length / float64(n)
+	cooling := math.Pow(1e-3, 1/float64(iterations))
+	for it := 0; it < iterations; it, temperature = it+1, temperature*cooling {
+		// Reverse the part of the tour from i+1 to j
+		i := r.Intn(n - 2)
+		j := i + 2 + r.Intn(n-i-2)
+		if i == 0 && j == n-1 {
+			continue
+		}
+		a, b, c, e := t[i], t[i+1], t[j], t[(j+1)%n]
+		delta := d[a][c] + d[b][e] - d[a][b] - d[c][e]
+		if delta >= 0 && (temperature <= 0 || r.Float64() >= math.Exp(-delta/temperature)) {
+			continue
+		}
+
+		reverseInts(t[i+1 : j+1])
+		length += delta
+		if length < bestLength-tourEpsilon {
+			copy(best, t)
+			bestLength = length
+			reportTour(g, v, best)
+		}
+	}
+	return newTour(g, d, best), nil
+}
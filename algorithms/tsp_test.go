@@ -0,0 +1,263 @@
+package algorithms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/han-so1omon/graphtools/structures"
+)
+
+func TestTour(t *testing.T) {
+	log.Printf("Testing tours")
+
+	// The corners of a unit square, numbered so that visiting them in order
+	// crosses the square twice
+	square := func(t *testing.T) *structures.Graph {
+		g := mockGraph(t, 4, nil)
+		for id, p := range [][2]float64{{0, 0}, {1, 1}, {1, 0}, {0, 1}} {
+			n, _ := g.GetNodeByID(id)
+			n.Coords = structures.Point{X: p[0], Y: p[1]}
+		}
+		return g
+	}
+	crossing := 2 + 2*math.Sqrt2
+
+	algs := map[string]func(*structures.Graph, *Visitor) (*Tour, error){
+		"HeldKarp": HeldKarp,
+		"NearestNeighbor": func(g *structures.Graph, v *Visitor) (*Tour, error) {
+			return NearestNeighborTour(g, v, 0)
+		},
+		"Christofides": func(g *structures.Graph, v *Visitor) (*Tour, error) {
+			return Christofides(g, v), nil
+		},
+		"TwoOpt": func(g *structures.Graph, v *Visitor) (*Tour, error) {
+			return TwoOpt(g, v, nil)
+		},
+		"OrOpt": func(g *structures.Graph, v *Visitor) (*Tour, error) {
+			return OrOpt(g, v, nil)
+		},
+		"SimulatedAnnealing": func(g *structures.Graph, v *Visitor) (*Tour, error) {
+			return SimulatedAnnealing(g, v, nil, 1000, 1)
+		},
+	}
+	for name, alg := range algs {
+		t.Run(name, func(t *testing.T) {
+			g := square(t)
+			var reported [][]int
+			v := &Visitor{UpdateTour: func(tour []int) {
+				reported = append(reported, tour)
+			}}
+			tour, err := alg(g, v)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find tour: %v", err))
+			}
+			checkTour(t, g, tour, 4)
+			if len(reported) == 0 {
+				t.Fatalf("Tour should be reported to the visitor")
+			}
+			checkInts(t, "Last reported tour", reported[len(reported)-1], tour.Nodes)
+
+			// Edges take the place of coordinates, so the shortest tour of
+			// nodes along a line returns along an edge from the last to the
+			// first
+			g = mockUndirectedGraph(t, 4, []mockEdge{{3, 0, 1}})
+			tour, _ = alg(g, nil)
+			if name != "NearestNeighbor" {
+				checkTour(t, g, tour, 4)
+			}
+
+			// Trivial tours
+			tour, _ = alg(mockGraph(t, 1, nil), nil)
+			checkTour(t, mockGraph(t, 1, nil), tour, 0)
+			tour, _ = alg(mockGraph(t, 2, nil), nil)
+			checkTour(t, mockGraph(t, 2, nil), tour, 2)
+		})
+	}
+
+	t.Run("Improvements", func(t *testing.T) {
+		g := square(t)
+		for name, improve := range map[string]func(*structures.Graph, *Visitor, []int) (*Tour, error){
+			"TwoOpt": TwoOpt,
+			"OrOpt":  OrOpt,
+		} {
+			var lengths []float64
+			d := tourDistances(g)
+			v := &Visitor{UpdateTour: func(tour []int) {
+				p, _ := tourPositions(g, tour)
+				lengths = append(lengths, tourLength(d, p))
+			}}
+			tour, err := improve(g, v, []int{0, 1, 2, 3})
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not improve tour: %v", err))
+			}
+			checkTour(t, g, tour, 4)
+			if lengths[0] != crossing {
+				t.Fatalf(fmt.Sprintf("%s should first report the given tour of length %f but reported %v", name, crossing, lengths))
+			}
+			for i := 1; i < len(lengths); i++ {
+				if lengths[i] >= lengths[i-1] {
+					t.Fatalf(fmt.Sprintf("%s should report shorter tours but reported %v", name, lengths))
+				}
+			}
+		}
+	})
+
+	t.Run("Random instances", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 50; i++ {
+			n := 3 + r.Intn(6)
+			var edges []mockEdge
+			for a := 0; a < n; a++ {
+				for b := a + 1; b < n; b++ {
+					if r.Intn(4) == 0 {
+						edges = append(edges, mockEdge{a, b, 1 + float64(r.Intn(5))})
+					}
+				}
+			}
+			g := mockUndirectedGraph(t, n, edges)
+			for _, n := range g.Nodes {
+				n.Coords = structures.Point{X: 10 * r.Float64(), Y: 10 * r.Float64()}
+			}
+
+			best := bruteForceTour(tourDistances(g))
+			exact, err := HeldKarp(g, nil)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find exact tour: %v", err))
+			}
+			checkTour(t, g, exact, best)
+
+			start, _ := NearestNeighborTour(g, nil, 0)
+			checkTour(t, g, start, start.Length)
+			for name, tour := range map[string]func() (*Tour, error){
+				"2-opt":               func() (*Tour, error) { return TwoOpt(g, nil, start.Nodes) },
+				"Or-opt":              func() (*Tour, error) { return OrOpt(g, nil, start.Nodes) },
+				"Simulated annealing": func() (*Tour, error) { return SimulatedAnnealing(g, nil, start.Nodes, 1000, 1) },
+			} {
+				improved, err := tour()
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("Could not improve tour: %v", err))
+				}
+				checkTour(t, g, improved, improved.Length)
+				if improved.Length > start.Length+tourEpsilon || improved.Length < best-tourEpsilon {
+					t.Fatalf(fmt.Sprintf(
+						"%s tour should be between %f and %f long but is %f", name, best, start.Length, improved.Length,
+					))
+				}
+			}
+
+			// The edge weights may break the triangle inequality, but
+			// coordinates alone do not
+			g = mockGraph(t, n, nil)
+			for _, n := range g.Nodes {
+				n.Coords = structures.Point{X: 10 * r.Float64(), Y: 10 * r.Float64()}
+			}
+			best = bruteForceTour(tourDistances(g))
+			c := Christofides(g, nil)
+			checkTour(t, g, c, c.Length)
+			if c.Length > 1.5*best+tourEpsilon {
+				t.Fatalf(fmt.Sprintf("Christofides tour should be at most %f long but is %f", 1.5*best, c.Length))
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		g := square(t)
+		var tourErr *TourError
+		for _, tour := range [][]int{{0, 1, 2}, {0, 1, 2, 2}, {0, 1, 2, 3, 4}} {
+			if _, err := TwoOpt(g, nil, tour); !errors.As(err, &tourErr) {
+				t.Fatalf(fmt.Sprintf("Improving tour %v should fail with TourError", tour))
+			}
+		}
+
+		var noNode *structures.NoNodeError
+		if _, err := NearestNeighborTour(g, nil, 4); !errors.As(err, &noNode) {
+			t.Fatalf("Tour from a missing node should fail with NoNodeError")
+		}
+
+		var size *TourSizeError
+		if _, err := HeldKarp(mockGraph(t, HeldKarpMaxNodes+1, nil), nil); !errors.As(err, &size) {
+			t.Fatalf("Exact tour of too many nodes should fail with TourSizeError")
+		}
+	})
+
+	t.Run("Animation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		mgr := structures.NewGenericGraphManager(ctx, cancel, 100)
+		mgr.Graph = square(t)
+		for _, e := range [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}, {0, 2}} {
+			mgr.Graph.SetEdgeByNodeID(e[0], e[1], EuclideanDistance(
+				mgr.Graph.Nodes[e[0]].Coords, mgr.Graph.Nodes[e[1]].Coords,
+			), "n", "n", true)
+		}
+		go func() {
+			for range mgr.Updated() {
+			}
+		}()
+
+		run := NewRunManager(mgr, mgr.Graph)
+		run.StepDelay = 0
+		run.Run("TwoOpt", func(v *Visitor) error {
+			_, err := TwoOpt(mgr.Graph, v, []int{0, 1, 2, 3})
+			return err
+		})
+		run.Pause()
+		for k, want := range map[EdgeKey]State{
+			{0, 2}: Selected, {2, 0}: Selected, {2, 1}: Selected, {3, 0}: Selected,
+			{0, 1}: Unvisited, {2, 3}: Unvisited,
+		} {
+			if s := run.edgeStates[k]; s != want {
+				t.Fatalf(fmt.Sprintf("Edge %v should be in state %d but is in state %d", k, want, s))
+			}
+		}
+		run.Done()
+	})
+}
+
+// checkTour checks that a tour visits every node of g once and has the wanted
+// length
+func checkTour(t *testing.T, g *structures.Graph, tour *Tour, length float64) {
+	t.Helper()
+	p, err := tourPositions(g, tour.Nodes)
+	if err != nil || len(tour.Nodes) != len(g.Nodes) {
+		t.Fatalf(fmt.Sprintf("Tour %v should visit every node once: %v", tour.Nodes, err))
+	}
+	if got := tourLength(tourDistances(g), p); math.Abs(got-tour.Length) > 1e-9 {
+		t.Fatalf(fmt.Sprintf("Tour %v should be %f long as reported but is %f", tour.Nodes, tour.Length, got))
+	}
+	if math.Abs(tour.Length-length) > 1e-9 {
+		t.Fatalf(fmt.Sprintf("Tour %v should be %f long but is %f", tour.Nodes, length, tour.Length))
+	}
+}
+
+// bruteForceTour returns the length of the shortest tour through every node
+// position of the distances d, starting from position 0
+func bruteForceTour(d [][]float64) float64 {
+	best := math.Inf(1)
+	t := []int{0}
+	used := make([]bool, len(d))
+	used[0] = true
+	var extend func()
+	extend = func() {
+		if len(t) == len(d) {
+			best = math.Min(best, tourLength(d, t))
+			return
+		}
+		for p := range d {
+			if !used[p] {
+				used[p] = true
+				t = append(t, p)
+				extend()
+				t = t[:len(t)-1]
+				used[p] = false
+			}
+		}
+	}
+	extend()
+	return best
+}
@@ -0,0 +1,49 @@
+package algorithms
+
+// unionFind is a disjoint set forest over node IDs with union by rank and path
+// halving. IDs are added to their own set on first use
+type unionFind struct {
+	parent map[int]int
+	rank   map[int]int
+}
+
+func newUnionFind() *unionFind {
+	return &unionFind{
+		parent: make(map[int]int),
+		rank:   make(map[int]int),
+	}
+}
+
+// find returns the representative ID of the set holding id
+func (u *unionFind) find(id int) int {
+	p, ok := u.parent[id]
+	if !ok {
+		u.parent[id] = id
+		return id
+	}
+	for p != id {
+		// Point id at its grandparent to halve the path for later finds
+		gp := u.parent[p]
+		u.parent[id] = gp
+		id, p = gp, u.parent[gp]
+	}
+	return id
+}
+
+// union merges the sets holding a and b. It returns false if they were already
+// the same set
+func (u *unionFind) union(a, b int) bool {
+	a, b = u.find(a), u.find(b)
+	if a == b {
+		return false
+	}
+
+	if u.rank[a] < u.rank[b] {
+		a, b = b, a
+	}
+	u.parent[b] = a
+	if u.rank[a] == u.rank[b] {
+		u.rank[a]++
+	}
+	return true
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	//"encoding/json"
 	//"github.com/gorilla/websocket"
 	"github.com/julienschmidt/httprouter"
@@ -9,7 +10,9 @@ import (
 	"net/http"
 	"nhooyr.io/websocket"
 	"nhooyr.io/websocket/wsjson"
+	"sync"
 
+	"github.com/han-so1omon/graphtools/algorithms"
 	"github.com/han-so1omon/graphtools/structures"
 )
 
@@ -49,6 +52,822 @@ func sendGraph(ctx context.Context, ws *websocket.Conn, g *structures.GraphDispl
 	}
 }
 
+// genericGraphManager returns the generic graph manager held by g, unwrapping
+// the algorithm run that may be animating it
+func genericGraphManager(g *structures.GraphDisplayManager) (*structures.GenericGraphManager, bool) {
+	if g == nil || *g == nil {
+		return nil, false
+	}
+	mgr := *g
+	if run, ok := mgr.(*algorithms.RunManager); ok {
+		mgr = run.GraphDisplayManager
+	}
+	gm, ok := mgr.(*structures.GenericGraphManager)
+	return gm, ok
+}
+
+// floatParam returns the named instruction parameter as a float
+func floatParam(params map[string]interface{}, name string) (float64, bool) {
+	v, ok := params[name].(float64)
+	return v, ok
+}
+
+// intParam returns the named instruction parameter as an integer. JSON numbers
+// are decoded as floats, so they are truncated
+func intParam(params map[string]interface{}, name string) (int, bool) {
+	v, ok := floatParam(params, name)
+	return int(v), ok
+}
+
+// stringParam returns the named instruction parameter as a string
+func stringParam(params map[string]interface{}, name string) (string, bool) {
+	v, ok := params[name].(string)
+	return v, ok
+}
+
+// boolParam returns the named instruction parameter as a boolean
+func boolParam(params map[string]interface{}, name string) (bool, bool) {
+	v, ok := params[name].(bool)
+	return v, ok
+}
+
+// controlRun handles playback actions for the algorithm run held by g
+func controlRun(g *structures.GraphDisplayManager, instruction Instruction) error {
+	if g == nil || *g == nil {
+		return internalError{ServerErrorType, "No algorithm is running"}
+	}
+	run, ok := (*g).(*algorithms.RunManager)
+	if !ok {
+		return internalError{ServerErrorType, "No algorithm is running"}
+	}
+
+	switch instruction.Action {
+	case "Play":
+		run.Play()
+	case "Pause":
+		run.Pause()
+	case "StepForward":
+		run.StepForward()
+	case "StepBack":
+		run.StepBack()
+	case "Seek":
+		step, ok := intParam(instruction.Params, "step")
+		if !ok {
+			return internalError{ServerErrorType, "Seek requires a step"}
+		}
+		run.Seek(step)
+	case "SetSpeed":
+		speed, ok := floatParam(instruction.Params, "stepsPerSecond")
+		if !ok || speed <= 0 {
+			return internalError{ServerErrorType, "SetSpeed requires positive stepsPerSecond"}
+		}
+		run.SetSpeed(speed)
+	default:
+		return internalError{ServerErrorType, "Unknown algorithm action " + instruction.Action}
+	}
+	return nil
+}
+
+// newRun wraps the generic graph held by g in a new algorithm run, which
+// replaces g as the structure sent to the client. It must be called with
+// instructionLock held
+func newRun(g *structures.GraphDisplayManager) (*algorithms.RunManager, error) {
+	mgr, ok := genericGraphManager(g)
+	if !ok {
+		return nil, internalError{ServerErrorType, "No generic graph loaded"}
+	}
+
+	// Stop playback of any previous run before recoloring the graph
+	if prev, ok := (*g).(*algorithms.RunManager); ok {
+		prev.Pause()
+	}
+
+	run := algorithms.NewRunManager(mgr, mgr.Graph)
+	*g = run
+	return run, nil
+}
+
+// runTraversal animates a breadth-first or depth-first traversal of the generic
+// graph held by g. The traversal starts from the "source" parameter if one is
+// given, otherwise it covers the whole graph
+func runTraversal(g *structures.GraphDisplayManager, instruction Instruction) error {
+	var sources []int
+	if source, ok := intParam(instruction.Params, "source"); ok {
+		sources = append(sources, source)
+	}
+
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var err error
+		if instruction.Action == "BFS" {
+			_, err = algorithms.BreadthFirstSearch(run.Graph, v, sources...)
+		} else {
+			_, err = algorithms.DepthFirstSearch(run.Graph, v, sources...)
+		}
+		return err
+	})
+}
+
+// runShortestPath animates a shortest path search of the generic graph held by
+// g from the "source" parameter and highlights the shortest path to the
+// "target" parameter. Every action but AStar searches the whole reachable graph
+// if no target is given. If Bellman-Ford or SPFA find a negative cycle, the
+// cycle is highlighted instead
+func runShortestPath(g *structures.GraphDisplayManager, instruction Instruction) error {
+	source, ok := intParam(instruction.Params, "source")
+	if !ok {
+		return internalError{ServerErrorType, instruction.Action + " requires a source"}
+	}
+	target, hasTarget := intParam(instruction.Params, "target")
+	if !hasTarget && instruction.Action == "AStar" {
+		return internalError{ServerErrorType, instruction.Action + " requires a target"}
+	}
+
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var (
+			p   *algorithms.Path
+			sp  *algorithms.ShortestPaths
+			err error
+		)
+		switch instruction.Action {
+		case "AStar":
+			p, _, err = algorithms.AStar(run.Graph, v, source, target, nil)
+		case "Dijkstra":
+			if hasTarget {
+				p, _, err = algorithms.DijkstraPath(run.Graph, v, source, target)
+			} else {
+				_, err = algorithms.Dijkstra(run.Graph, v, source)
+			}
+		case "BellmanFord":
+			sp, err = algorithms.BellmanFord(run.Graph, v, source)
+		case "SPFA":
+			sp, err = algorithms.SPFA(run.Graph, v, source)
+		}
+
+		var negativeCycle *algorithms.NegativeCycleError
+		if errors.As(err, &negativeCycle) && len(negativeCycle.Cycle) > 0 {
+			log.Println("Found negative cycle: ", negativeCycle.Cycle)
+			cycle := append(negativeCycle.Cycle, negativeCycle.Cycle[0])
+			run.SelectPath(&algorithms.Path{Nodes: cycle})
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if sp != nil && hasTarget {
+			p, err = sp.PathTo(target)
+			if err != nil {
+				return err
+			}
+		}
+		if p != nil {
+			run.SelectPath(p)
+		}
+		return nil
+	})
+}
+
+// runSpanningForest animates a minimum spanning forest search of the generic
+// graph held by g, highlighting each forest edge as it is selected
+func runSpanningForest(g *structures.GraphDisplayManager, instruction Instruction) error {
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var (
+			f   *algorithms.SpanningForest
+			err error
+		)
+		switch instruction.Action {
+		case "Prim":
+			f, err = algorithms.Prim(run.Graph, v)
+		case "Kruskal":
+			f, err = algorithms.Kruskal(run.Graph, v)
+		case "Boruvka":
+			f, err = algorithms.Boruvka(run.Graph, v)
+		}
+		if err != nil {
+			return err
+		}
+		log.Println("Found spanning forest of weight ", f.Weight)
+		return nil
+	})
+}
+
+// runDAG animates a topological sort or critical path search of the generic
+// graph held by g, highlighting the critical path. If the graph has a cycle,
+// the cycle is highlighted instead
+func runDAG(g *structures.GraphDisplayManager, instruction Instruction) error {
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var (
+			p   *algorithms.Path
+			err error
+		)
+		if instruction.Action == "TopologicalSort" {
+			var order []int
+			order, err = algorithms.TopologicalSort(run.Graph, v)
+			if err == nil {
+				log.Println("Found topological order: ", order)
+			}
+		} else {
+			p, err = algorithms.LongestPath(run.Graph, v)
+		}
+
+		var cycle *algorithms.CycleError
+		if errors.As(err, &cycle) && len(cycle.Cycle) > 0 {
+			log.Println("Found cycle: ", cycle.Cycle)
+			run.SelectPath(&algorithms.Path{Nodes: append(cycle.Cycle, cycle.Cycle[0])})
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if p != nil {
+			run.SelectPath(p)
+		}
+		return nil
+	})
+}
+
+// runBiconnectivity animates the search for single points of failure of the
+// generic graph held by g, highlighting its bridges and articulation points
+func runBiconnectivity(g *structures.GraphDisplayManager, instruction Instruction) error {
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		b := algorithms.FindBiconnectivity(run.Graph, v)
+		for _, id := range b.ArticulationPoints {
+			run.SetNodeState(id, algorithms.Selected)
+		}
+		for _, k := range b.Bridges {
+			run.SetEdgeState(k, algorithms.Selected)
+			run.SetEdgeState(k.Reverse(), algorithms.Selected)
+		}
+		run.TakeStep()
+		return nil
+	})
+}
+
+// runMaxFlow animates a maximum flow search of the generic graph held by g from
+// the "source" parameter to the "target" parameter. Edges are recolored as
+// their flow changes, and the minimum cut and its source side are highlighted
+// once the flow is found. Minimum cost flows also log their cost
+func runMaxFlow(g *structures.GraphDisplayManager, instruction Instruction) error {
+	source, ok := intParam(instruction.Params, "source")
+	if !ok {
+		return internalError{ServerErrorType, instruction.Action + " requires a source"}
+	}
+	target, ok := intParam(instruction.Params, "target")
+	if !ok {
+		return internalError{ServerErrorType, instruction.Action + " requires a target"}
+	}
+
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var (
+			f   *algorithms.Flow
+			err error
+		)
+		switch instruction.Action {
+		case "EdmondsKarp":
+			f, err = algorithms.EdmondsKarp(run.Graph, v, source, target)
+		case "Dinic":
+			f, err = algorithms.Dinic(run.Graph, v, source, target)
+		case "PushRelabel":
+			f, err = algorithms.PushRelabel(run.Graph, v, source, target)
+		case "SuccessiveShortestPaths", "CostScaling":
+			var c *algorithms.CostFlow
+			if instruction.Action == "SuccessiveShortestPaths" {
+				c, err = algorithms.SuccessiveShortestPaths(run.Graph, v, source, target)
+			} else {
+				c, err = algorithms.CostScaling(run.Graph, v, source, target)
+			}
+			if err != nil {
+				return err
+			}
+			log.Println("Found minimum cost flow costing ", c.Cost)
+			f = &c.Flow
+		}
+		if err != nil {
+			return err
+		}
+
+		log.Println("Found maximum flow of ", f.Value)
+		for _, id := range f.SourceSide {
+			run.SetNodeState(id, algorithms.Selected)
+		}
+		for _, k := range f.MinCut {
+			run.SetEdgeState(k, algorithms.Selected)
+		}
+		run.TakeStep()
+		return nil
+	})
+}
+
+// runMatching animates a matching of the generic graph held by g, highlighting
+// the matched edges as they change and the blossoms as they are contracted.
+// "Bipartite" instead highlights the left side of a two-coloring. Graphs that
+// are not bipartite have an odd cycle highlighted by the bipartite algorithms.
+// "WeightedBlossom" takes an optional "maxCardinality" parameter
+func runMatching(g *structures.GraphDisplayManager, instruction Instruction) error {
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var (
+			b   *algorithms.Bipartition
+			m   *algorithms.Matching
+			err error
+		)
+		switch instruction.Action {
+		case "Bipartite":
+			b, err = algorithms.Bipartite(run.Graph, v)
+		case "HopcroftKarp":
+			m, err = algorithms.HopcroftKarp(run.Graph, v)
+		case "Hungarian":
+			m, err = algorithms.Hungarian(run.Graph, v)
+		case "Blossom":
+			m = algorithms.Blossom(run.Graph, v)
+		case "WeightedBlossom":
+			maxCardinality, _ := boolParam(instruction.Params, "maxCardinality")
+			m = algorithms.WeightedBlossom(run.Graph, v, maxCardinality)
+		}
+
+		var odd *algorithms.OddCycleError
+		if errors.As(err, &odd) {
+			log.Println("Found odd cycle: ", odd.Cycle)
+			for i, id := range odd.Cycle {
+				k := algorithms.EdgeKey{From: id, To: odd.Cycle[(i+1)%len(odd.Cycle)]}
+				run.SetNodeState(id, algorithms.Selected)
+				run.SetEdgeState(k, algorithms.Selected)
+				run.SetEdgeState(k.Reverse(), algorithms.Selected)
+			}
+			run.TakeStep()
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if b != nil {
+			log.Println("Found bipartition: ", b.Left, b.Right)
+			for _, id := range b.Left {
+				run.SetNodeState(id, algorithms.Selected)
+			}
+			run.TakeStep()
+			return nil
+		}
+		log.Println("Found matching of ", len(m.Edges), " edges weighing ", m.Weight)
+		return nil
+	})
+}
+
+// runTour animates a traveling salesman tour of the generic graph held by g,
+// highlighting the tour each time it improves. Nodes without an edge between
+// them are as far apart as their coordinates. "NearestNeighbor" takes an
+// optional "start" parameter, which is the first node otherwise. The tour
+// improvement heuristics start from that nearest neighbor tour, and
+// "SimulatedAnnealing" takes optional "iterations" and "seed" parameters
+func runTour(g *structures.GraphDisplayManager, instruction Instruction) error {
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	start, ok := intParam(instruction.Params, "start")
+	if !ok && len(run.Graph.Nodes) > 0 {
+		start = run.Graph.Nodes[0].ID
+	}
+	iterations, ok := intParam(instruction.Params, "iterations")
+	if !ok {
+		iterations = 10000
+	}
+	seed, _ := intParam(instruction.Params, "seed")
+
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var (
+			tour *algorithms.Tour
+			err  error
+		)
+		switch instruction.Action {
+		case "HeldKarp":
+			tour, err = algorithms.HeldKarp(run.Graph, v)
+		case "NearestNeighbor":
+			tour, err = algorithms.NearestNeighborTour(run.Graph, v, start)
+		case "Christofides":
+			tour = algorithms.Christofides(run.Graph, v)
+		case "TwoOpt", "OrOpt", "SimulatedAnnealing":
+			tour, err = algorithms.NearestNeighborTour(run.Graph, nil, start)
+			if err != nil {
+				return err
+			}
+			switch instruction.Action {
+			case "TwoOpt":
+				tour, err = algorithms.TwoOpt(run.Graph, v, tour.Nodes)
+			case "OrOpt":
+				tour, err = algorithms.OrOpt(run.Graph, v, tour.Nodes)
+			default:
+				tour, err = algorithms.SimulatedAnnealing(run.Graph, v, tour.Nodes, iterations, int64(seed))
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		log.Println("Found tour ", tour.Nodes, " of length ", tour.Length)
+		return nil
+	})
+}
+
+// runEulerian animates an Eulerian trail, Eulerian circuit or Chinese postman
+// walk of the generic graph held by g, highlighting the walk once it is found.
+// The graph is viewed as undirected unless the optional "directed" parameter
+// is set. When the degrees rule out a trail, the offending nodes are
+// highlighted instead
+func runEulerian(g *structures.GraphDisplayManager, instruction Instruction) error {
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	directed, _ := boolParam(instruction.Params, "directed")
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var (
+			p   *algorithms.Path
+			err error
+		)
+		switch instruction.Action {
+		case "EulerianTrail":
+			p, err = algorithms.EulerianTrail(run.Graph, v, directed)
+		case "EulerianCircuit":
+			p, err = algorithms.EulerianCircuit(run.Graph, v, directed)
+		case "ChinesePostman":
+			var postman *algorithms.Postman
+			postman, err = algorithms.ChinesePostman(run.Graph, v, directed)
+			if err == nil {
+				log.Println("Duplicated edges: ", postman.Duplicated)
+				p = postman.Circuit
+			}
+		}
+
+		var degree *algorithms.DegreeError
+		if errors.As(err, &degree) {
+			log.Println(degree)
+			for _, id := range degree.Nodes {
+				run.SetNodeState(id, algorithms.Selected)
+			}
+			run.TakeStep()
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		log.Println("Found walk ", p.Nodes, " of cost ", p.Cost)
+		if !directed {
+			for _, k := range p.Edges() {
+				run.SetEdgeState(k.Reverse(), algorithms.Selected)
+			}
+		}
+		run.SelectPath(p)
+		return nil
+	})
+}
+
+// runKPaths animates alternative paths from the "source" parameter to the
+// "target" parameter of the generic graph held by g, highlighting each path
+// in turn and leaving the earlier ones visited. "KShortestPaths" and
+// "KShortestWalks" take an optional "k" parameter, which is 3 by default, and
+// "SimplePaths" takes optional "maxEdges" and "maxCost" limits
+func runKPaths(g *structures.GraphDisplayManager, instruction Instruction) error {
+	source, ok := intParam(instruction.Params, "source")
+	if !ok {
+		return internalError{ServerErrorType, instruction.Action + " requires a source"}
+	}
+	target, ok := intParam(instruction.Params, "target")
+	if !ok {
+		return internalError{ServerErrorType, instruction.Action + " requires a target"}
+	}
+	k, ok := intParam(instruction.Params, "k")
+	if !ok {
+		k = 3
+	}
+	maxEdges, _ := intParam(instruction.Params, "maxEdges")
+	maxCost, _ := floatParam(instruction.Params, "maxCost")
+
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var (
+			paths []*algorithms.Path
+			err   error
+		)
+		switch instruction.Action {
+		case "KShortestPaths":
+			paths, err = algorithms.KShortestPaths(run.Graph, source, target, k)
+		case "KShortestWalks":
+			paths, err = algorithms.KShortestWalks(run.Graph, source, target, k)
+		case "SimplePaths":
+			paths, err = algorithms.SimplePaths(run.Graph, source, target, maxEdges, maxCost)
+		}
+		if err != nil {
+			return err
+		}
+
+		for i, p := range paths {
+			log.Println("Found path ", p.Nodes, " of cost ", p.Cost)
+			if i > 0 {
+				for _, id := range paths[i-1].Nodes {
+					run.SetNodeState(id, algorithms.Visited)
+				}
+				for _, k := range paths[i-1].Edges() {
+					run.SetEdgeState(k, algorithms.Visited)
+				}
+			}
+			run.SelectPath(p)
+		}
+		return nil
+	})
+}
+
+// runCliques animates the clique, independent set or vertex cover named by the
+// action in the generic graph held by g viewed as undirected. "MaximalCliques"
+// highlights each maximal clique in turn, leaving the earlier ones visited,
+// and the other actions highlight the set they find along with the edges
+// inside it
+func runCliques(g *structures.GraphDisplayManager, instruction Instruction) error {
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var sets [][]int
+		switch instruction.Action {
+		case "MaximalCliques":
+			sets = algorithms.MaximalCliques(run.Graph)
+		case "MaximumClique":
+			sets = [][]int{algorithms.MaximumClique(run.Graph)}
+		case "MaximumIndependentSet":
+			sets = [][]int{algorithms.MaximumIndependentSet(run.Graph)}
+		case "MinimumVertexCover":
+			sets = [][]int{algorithms.MinimumVertexCover(run.Graph)}
+		case "GreedyClique":
+			sets = [][]int{algorithms.GreedyClique(run.Graph)}
+		case "GreedyIndependentSet":
+			sets = [][]int{algorithms.GreedyIndependentSet(run.Graph)}
+		case "ApproximateVertexCover":
+			sets = [][]int{algorithms.ApproximateVertexCover(run.Graph)}
+		}
+
+		show := func(set []int, s algorithms.State) {
+			for i, a := range set {
+				run.SetNodeState(a, s)
+				for _, b := range set[i+1:] {
+					run.SetEdgeState(algorithms.EdgeKey{From: a, To: b}, s)
+					run.SetEdgeState(algorithms.EdgeKey{From: b, To: a}, s)
+				}
+			}
+		}
+		for i, set := range sets {
+			log.Println("Found set ", set)
+			if i > 0 {
+				show(sets[i-1], algorithms.Visited)
+			}
+			show(set, algorithms.Selected)
+			run.TakeStep()
+		}
+		return nil
+	})
+}
+
+// runIsomorphism animates the occurrences of a pattern graph, given in CSV form
+// by the "patternCSV" parameter, in the generic graph held by g, highlighting
+// the image of each mapping in turn and leaving the earlier ones visited.
+// "Isomorphism" maps the pattern onto the whole graph, while
+// "SubgraphIsomorphism" maps it into part of the graph and takes an optional
+// "induced" parameter. Both take an optional "limit" on the number of mappings
+func runIsomorphism(g *structures.GraphDisplayManager, instruction Instruction) error {
+	csvText, ok := stringParam(instruction.Params, "patternCSV")
+	if !ok {
+		return internalError{ServerErrorType, instruction.Action + " requires a patternCSV"}
+	}
+	pattern, err := structures.LoadCSV(context.Background(), nil, csvText)
+	if err != nil {
+		return err
+	}
+	if pattern == nil {
+		return internalError{ServerErrorType, "patternCSV holds no graph"}
+	}
+	induced, _ := boolParam(instruction.Params, "induced")
+	limit, _ := intParam(instruction.Params, "limit")
+
+	run, err := newRun(g)
+	if err != nil {
+		return err
+	}
+	return run.Run(instruction.Action, func(v *algorithms.Visitor) error {
+		var mappings []map[int]int
+		if instruction.Action == "Isomorphism" {
+			mappings = algorithms.Isomorphisms(pattern.Graph, run.Graph, nil, limit)
+		} else {
+			mappings = algorithms.SubgraphIsomorphisms(pattern.Graph, run.Graph, nil, induced, limit)
+		}
+		log.Println("Found ", len(mappings), " mappings")
+
+		show := func(m map[int]int, s algorithms.State) {
+			for _, n := range pattern.Graph.Nodes {
+				run.SetNodeState(m[n.ID], s)
+				for _, e := range n.Edges {
+					run.SetEdgeState(algorithms.EdgeKey{From: m[n.ID], To: m[e.Nodes[1].ID]}, s)
+				}
+			}
+		}
+		for i, m := range mappings {
+			log.Println("Found mapping ", m)
+			if i > 0 {
+				show(mappings[i-1], algorithms.Visited)
+			}
+			show(m, algorithms.Selected)
+			run.TakeStep()
+		}
+		return nil
+	})
+}
+
+// showCentrality scores the nodes of the generic graph held by g with the
+// centrality measure named by the "measure" parameter and shows the scores as
+// node colors and sizes. Distance based measures take an optional "weighted"
+// parameter, Katz centrality optional "alpha" and "beta" parameters and
+// PageRank an optional "damping" parameter. Any algorithm run is stopped so
+// that the graph itself is displayed
+func showCentrality(g *structures.GraphDisplayManager, instruction Instruction) error {
+	mgr, ok := genericGraphManager(g)
+	if !ok {
+		return internalError{ServerErrorType, "No generic graph loaded"}
+	}
+	measure, _ := stringParam(instruction.Params, "measure")
+	weighted, _ := boolParam(instruction.Params, "weighted")
+	alpha, ok := floatParam(instruction.Params, "alpha")
+	if !ok {
+		alpha = 0.1
+	}
+	beta, ok := floatParam(instruction.Params, "beta")
+	if !ok {
+		beta = 1
+	}
+	damping, ok := floatParam(instruction.Params, "damping")
+	if !ok {
+		damping = 0.85
+	}
+
+	if run, ok := (*g).(*algorithms.RunManager); ok {
+		run.Pause()
+	}
+	*g = mgr
+
+	mgr.Lock()
+	defer mgr.Unlock()
+	algorithms.ResetSizes(mgr.Graph)
+	var (
+		scores map[int]float64
+		err    error
+	)
+	switch measure {
+	case "Degree":
+		scores = algorithms.DegreeCentrality(mgr.Graph)
+	case "InDegree":
+		scores = algorithms.InDegreeCentrality(mgr.Graph)
+	case "OutDegree":
+		scores = algorithms.OutDegreeCentrality(mgr.Graph)
+	case "Closeness":
+		scores, err = algorithms.ClosenessCentrality(mgr.Graph, weighted)
+	case "Harmonic":
+		scores, err = algorithms.HarmonicCentrality(mgr.Graph, weighted)
+	case "Betweenness":
+		scores, err = algorithms.BetweennessCentrality(mgr.Graph, weighted)
+	case "Eigenvector":
+		scores, err = algorithms.EigenvectorCentrality(mgr.Graph)
+	case "Katz":
+		scores, err = algorithms.KatzCentrality(mgr.Graph, alpha, beta)
+	case "PageRank":
+		scores, err = algorithms.PageRank(mgr.Graph, damping)
+	default:
+		return internalError{ServerErrorType, "Unknown centrality measure " + measure}
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Println("Found ", measure, " centrality: ", scores)
+	algorithms.ShowScores(mgr.Graph, scores)
+	return nil
+}
+
+// showCommunities finds communities in the generic graph held by g with the
+// method named by the "method" parameter and shows them as node colors.
+// Label propagation takes an optional "seed" parameter. Any algorithm run is
+// stopped so that the graph itself is displayed
+func showCommunities(g *structures.GraphDisplayManager, instruction Instruction) error {
+	mgr, ok := genericGraphManager(g)
+	if !ok {
+		return internalError{ServerErrorType, "No generic graph loaded"}
+	}
+	method, _ := stringParam(instruction.Params, "method")
+	seed, _ := intParam(instruction.Params, "seed")
+
+	if run, ok := (*g).(*algorithms.RunManager); ok {
+		run.Pause()
+	}
+	*g = mgr
+
+	mgr.Lock()
+	defer mgr.Unlock()
+	algorithms.ResetSizes(mgr.Graph)
+	var (
+		c   *algorithms.Communities
+		err error
+	)
+	switch method {
+	case "Louvain":
+		c, err = algorithms.Louvain(mgr.Graph)
+	case "LabelPropagation":
+		c = algorithms.LabelPropagation(mgr.Graph, int64(seed))
+	case "GirvanNewman":
+		c = algorithms.GirvanNewman(mgr.Graph)
+	default:
+		return internalError{ServerErrorType, "Unknown community detection method " + method}
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Println("Found ", len(c.Members), " communities with modularity ", c.Modularity, ": ", c.Members)
+	algorithms.ShowCommunities(mgr.Graph, c.Components)
+	return nil
+}
+
+// showColoring colors the nodes or edges of the generic graph held by g with
+// the method named by the "method" parameter. Any algorithm run is stopped so
+// that the graph itself is displayed
+func showColoring(g *structures.GraphDisplayManager, instruction Instruction) error {
+	mgr, ok := genericGraphManager(g)
+	if !ok {
+		return internalError{ServerErrorType, "No generic graph loaded"}
+	}
+	method, _ := stringParam(instruction.Params, "method")
+
+	if run, ok := (*g).(*algorithms.RunManager); ok {
+		run.Pause()
+	}
+	*g = mgr
+
+	mgr.Lock()
+	defer mgr.Unlock()
+	algorithms.ResetSizes(mgr.Graph)
+	var c *algorithms.Coloring
+	switch method {
+	case "LargestFirst":
+		c = algorithms.GreedyColoring(mgr.Graph, algorithms.LargestFirstOrder(mgr.Graph))
+	case "SmallestLast":
+		c = algorithms.GreedyColoring(mgr.Graph, algorithms.SmallestLastOrder(mgr.Graph))
+	case "DSatur":
+		c = algorithms.DSaturColoring(mgr.Graph)
+	case "Chromatic":
+		c = algorithms.ChromaticColoring(mgr.Graph)
+	case "Edge":
+		ec := algorithms.MisraGriesEdgeColoring(mgr.Graph)
+		log.Println("Found edge coloring with ", ec.NumColors, " colors")
+		algorithms.ShowEdgeColoring(mgr.Graph, ec)
+		return nil
+	default:
+		return internalError{ServerErrorType, "Unknown coloring method " + method}
+	}
+
+	log.Println("Found coloring with ", c.NumColors, " colors")
+	algorithms.ShowColoring(mgr.Graph, c)
+	return nil
+}
+
+// instructionLock serializes the handling of instructions, since connections
+// share the graph display manager held by the store and runs replace it
+var instructionLock sync.Mutex
+
 func handleInstruction(
 	ctx context.Context,
 	cancel context.CancelFunc,
@@ -57,6 +876,9 @@ func handleInstruction(
 	g *structures.GraphDisplayManager) {
 	var err error
 
+	instructionLock.Lock()
+	defer instructionLock.Unlock()
+
 	// Execute functions based off of instruction
 	// For functions that require locking the graph display manager, unlock immeadiately
 	// so that other routines can deal with tree once OnUpdate is called
@@ -110,6 +932,103 @@ func handleInstruction(
 				gr := (*g).(*structures.GenericGraphManager)
 				log.Println(gr.Graph)
 			*/
+		case "BFS", "DFS":
+			err = runTraversal(g, instruction)
+			if err != nil {
+				log.Println("Error running traversal: ", err)
+				return
+			}
+		case "Dijkstra", "AStar", "BellmanFord", "SPFA":
+			err = runShortestPath(g, instruction)
+			if err != nil {
+				log.Println("Error running shortest path: ", err)
+				return
+			}
+		case "Prim", "Kruskal", "Boruvka":
+			err = runSpanningForest(g, instruction)
+			if err != nil {
+				log.Println("Error running spanning forest: ", err)
+				return
+			}
+		case "TopologicalSort", "CriticalPath":
+			err = runDAG(g, instruction)
+			if err != nil {
+				log.Println("Error running DAG algorithm: ", err)
+				return
+			}
+		case "Biconnectivity":
+			err = runBiconnectivity(g, instruction)
+			if err != nil {
+				log.Println("Error running biconnectivity: ", err)
+				return
+			}
+		case "EdmondsKarp", "Dinic", "PushRelabel", "SuccessiveShortestPaths", "CostScaling":
+			err = runMaxFlow(g, instruction)
+			if err != nil {
+				log.Println("Error running maximum flow: ", err)
+				return
+			}
+		case "Bipartite", "HopcroftKarp", "Hungarian", "Blossom", "WeightedBlossom":
+			err = runMatching(g, instruction)
+			if err != nil {
+				log.Println("Error running matching: ", err)
+				return
+			}
+		case "HeldKarp", "NearestNeighbor", "Christofides", "TwoOpt", "OrOpt", "SimulatedAnnealing":
+			err = runTour(g, instruction)
+			if err != nil {
+				log.Println("Error running tour: ", err)
+				return
+			}
+		case "EulerianTrail", "EulerianCircuit", "ChinesePostman":
+			err = runEulerian(g, instruction)
+			if err != nil {
+				log.Println("Error running Eulerian walk: ", err)
+				return
+			}
+		case "KShortestPaths", "KShortestWalks", "SimplePaths":
+			err = runKPaths(g, instruction)
+			if err != nil {
+				log.Println("Error finding paths: ", err)
+				return
+			}
+		case "MaximalCliques", "MaximumClique", "MaximumIndependentSet", "MinimumVertexCover",
+			"GreedyClique", "GreedyIndependentSet", "ApproximateVertexCover":
+			err = runCliques(g, instruction)
+			if err != nil {
+				log.Println("Error finding cliques: ", err)
+				return
+			}
+		case "Isomorphism", "SubgraphIsomorphism":
+			err = runIsomorphism(g, instruction)
+			if err != nil {
+				log.Println("Error finding isomorphisms: ", err)
+				return
+			}
+		case "Centrality":
+			err = showCentrality(g, instruction)
+			if err != nil {
+				log.Println("Error finding centrality: ", err)
+				return
+			}
+		case "Communities":
+			err = showCommunities(g, instruction)
+			if err != nil {
+				log.Println("Error finding communities: ", err)
+				return
+			}
+		case "Coloring":
+			err = showColoring(g, instruction)
+			if err != nil {
+				log.Println("Error finding coloring: ", err)
+				return
+			}
+		}
+	} else if instruction.Structure == algorithms.AlgorithmRunType {
+		err = controlRun(g, instruction)
+		if err != nil {
+			sendInternalError(ctx, ws, err)
+			return
 		}
 	}
 	if g != nil && *g != nil {
@@ -131,6 +1050,16 @@ func receiveInstructions(
 	g *structures.GraphDisplayManager,
 ) {
 	log.Println("Ready to receive instructions")
+
+	// Handle instructions one at a time in the order they are received
+	instructions := make(chan Instruction, 16)
+	defer close(instructions)
+	go func() {
+		for instruction := range instructions {
+			handleInstruction(ctx, cancel, ws, instruction, g)
+		}
+	}()
+
 	done := false
 	newInstruction := false
 	for !done {
@@ -156,7 +1085,10 @@ func receiveInstructions(
 			newInstruction = true
 		}
 		if newInstruction {
-			go handleInstruction(ctx, cancel, ws, instruction, g)
+			select {
+			case instructions <- instruction:
+			case <-ctx.Done():
+			}
 		}
 		select {
 		case <-ctx.Done():
@@ -18,12 +18,17 @@ type NodeRepr struct {
 // Edge is uni-directional
 // Weight holds the value of the connection, which may indicate difficulty
 // or strength
+// Cost holds a second value of the connection, e.g. the cost per unit of flow
+// when Weight is a capacity
 // Nodes holds the representation of the connecting nodes
 // The first node is referred to as near, while the second node is referred to
 // as far
+// Extra holds optional display data, e.g. the color of the edge
 type Edge struct {
 	Weight float64    `json:"weight"`
+	Cost   float64    `json:"cost"`
 	Nodes  []NodeRepr `json:"noderepr"`
+	Extra  Data       `json:"extra,omitempty"`
 }
 
 // String is the string representation of an edge. This is useful formatted
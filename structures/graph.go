@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -64,6 +65,18 @@ type Comparable interface {
 	GetValue() int
 }
 
+// StorageOrder determines how a graph stores its nodes and their edges
+type StorageOrder int
+
+const (
+	// InsertionOrder stores nodes and edges in the order that they are added.
+	// Removing a node moves the last node into its place
+	InsertionOrder StorageOrder = iota
+	// SortedOrder stores nodes and edges sorted by their Comparable value so
+	// that they can be binary searched
+	SortedOrder
+)
+
 const (
 	// GenericGraphManagerType names the generic graph manager for use in API
 	// operations
@@ -162,6 +175,16 @@ func LoadCSV(ctx context.Context, cancel context.CancelFunc, csvText string) (*G
 				return nil, err
 			}
 			mgr.Graph.SetEdgeByNodeID(n1, n2, w, "n", "n", false)
+			// The edge cost is optional
+			if len(record) > 3 {
+				c, err := strconv.ParseFloat(record[3], 64)
+				if err != nil {
+					return nil, err
+				}
+				if err := mgr.Graph.SetEdgeCostByNodeID(n1, n2, c, false); err != nil {
+					return nil, err
+				}
+			}
 			//func (g *Graph) SetEdgeByNodeID(n1, n2 int, w float64, t1, t2 string, bidirectional bool) error {
 		} else {
 			break // done with CSV per specification
@@ -199,8 +222,16 @@ func (g *GenericGraphManager) Updated() <-chan struct{} {
 // It is the prerogative of graph owners (i.e. end-users, accompanying
 // structures, or algorithms) to call OnUpdate()
 func (g *GenericGraphManager) OnUpdate() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	// The lock is held while sending so that Done cannot close updated
+	// underneath the send
 	if !g.isDone {
-		g.updated <- struct{}{}
+		select {
+		case g.updated <- struct{}{}:
+		case <-g.ctx.Done():
+		}
 	}
 }
 
@@ -208,9 +239,14 @@ func (g *GenericGraphManager) OnUpdate() {
 // It is the prerogative of graph owners (i.e. end-users, accompanying
 // structures, or algorithms) to call Done()
 func (g *GenericGraphManager) Done() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
 	//g.cancel()
-	close(g.updated)
-	g.isDone = true
+	if !g.isDone {
+		close(g.updated)
+		g.isDone = true
+	}
 }
 
 // Lock is useful to be called when the graph needs to be accessed as an atomic
@@ -232,6 +268,14 @@ type Graph struct {
 	MaxEdgeWeight float64 `json:"maxEdgeWeight"`
 	Nodes         []*Node `json:"nodes"`
 
+	// order determines whether nodes and edges are kept sorted
+	order StorageOrder
+	// nodeIndex maps node IDs to nodes for constant time lookup
+	nodeIndex map[int]*Node
+	// nodePos maps node IDs to their position in Nodes for constant time
+	// removal. It is only maintained for InsertionOrder storage
+	nodePos map[int]int
+
 	// Control structures
 	Lock *sync.Mutex `json:"-"`
 }
@@ -247,18 +291,58 @@ func (g *Graph) String() string {
 	return b.String()
 }
 
-// NewGraph creates a new graph structure with a maximum edge weight value
+// NewGraph creates a new graph structure with a maximum edge weight value.
+// Nodes and edges are stored in insertion order
 func NewGraph(maxEdgeWeight float64) *Graph {
+	return NewGraphWithOrder(maxEdgeWeight, InsertionOrder)
+}
+
+// NewGraphWithOrder creates a new graph structure with a maximum edge weight
+// value that stores nodes and edges in the requested order
+func NewGraphWithOrder(maxEdgeWeight float64, order StorageOrder) *Graph {
 	rand.Seed(time.Now().UTC().UnixNano())
 	g := new(Graph)
 
 	g.Lock = &sync.Mutex{}
+	g.order = order
+	g.nodeIndex = make(map[int]*Node)
+	g.nodePos = make(map[int]int)
 
 	g.MaxEdgeWeight = maxEdgeWeight
 
 	return g
 }
 
+// StorageOrder returns how the graph stores nodes and edges
+func (g *Graph) StorageOrder() StorageOrder {
+	return g.order
+}
+
+// SetStorageOrder changes how the graph stores nodes and edges. Existing nodes
+// and edges are sorted when switching to SortedOrder
+func (g *Graph) SetStorageOrder(order StorageOrder) {
+	g.Lock.Lock()
+	defer g.Lock.Unlock()
+
+	g.order = order
+	g.nodePos = make(map[int]int)
+	if order == SortedOrder {
+		sort.Slice(g.Nodes, func(i, j int) bool {
+			return g.Nodes[i].Compare(g.Nodes[j]) < 0
+		})
+		for _, n := range g.Nodes {
+			edges := n.Edges
+			sort.Slice(edges, func(i, j int) bool {
+				return edges[i].Compare(edges[j]) < 0
+			})
+		}
+	} else {
+		for i, n := range g.Nodes {
+			g.nodePos[n.ID] = i
+		}
+	}
+}
+
 // IsEmpty returns whether or not the graph is empty
 func (g *Graph) IsEmpty() bool {
 	return g.NumNodes == 0
@@ -266,22 +350,17 @@ func (g *Graph) IsEmpty() bool {
 
 // HasNodeWithID returns whether the graph has a node with the specified ID
 func (g *Graph) HasNodeWithID(id int) bool {
-	for _, n := range g.Nodes {
-		if n.ID == id {
-			return true
-		}
-	}
-	return false
+	_, ok := g.nodeIndex[id]
+	return ok
 }
 
 // GetNodeByID returns the node with the specified ID
 func (g *Graph) GetNodeByID(id int) (*Node, error) {
-	for _, n := range g.Nodes {
-		if n.ID == id {
-			return n, nil
-		}
+	n, ok := g.nodeIndex[id]
+	if !ok {
+		return nil, &NoNodeError{id, nil}
 	}
-	return nil, &NoNodeError{id, nil}
+	return n, nil
 }
 
 // setNodeHelper is a non-blocking version of SetNode so that it can be called
@@ -297,7 +376,16 @@ func (g *Graph) setNodeHelper(n *Node, id int, x, y, z float64, extra Data) {
 
 	// Case adding new node
 	if !g.HasNodeWithID(id) {
-		g.Nodes = append(g.Nodes, n)
+		g.nodeIndex[id] = n
+		if g.order == SortedOrder {
+			i := searchNodes(g.Nodes, id)
+			g.Nodes = append(g.Nodes, nil)
+			copy(g.Nodes[i+1:], g.Nodes[i:])
+			g.Nodes[i] = n
+		} else {
+			g.nodePos[id] = len(g.Nodes)
+			g.Nodes = append(g.Nodes, n)
+		}
 		g.NumNodes++
 	}
 }
@@ -327,26 +415,49 @@ func (g *Graph) SetNodeByID(id int, x, y, z float64, extra Data) (*Node, error)
 	return n, nil
 }
 
+// removeNodeHelper removes a node from the node list and indices. For
+// InsertionOrder storage this takes constant time because the last node in the
+// list takes the place of the removed node. For SortedOrder storage the node is
+// binary searched and the remaining nodes are shifted to keep them sorted
+func (g *Graph) removeNodeHelper(n *Node) {
+	if !g.HasNodeWithID(n.ID) {
+		return
+	}
+
+	last := len(g.Nodes) - 1
+	if g.order == SortedOrder {
+		i := searchNodes(g.Nodes, n.ID)
+		copy(g.Nodes[i:], g.Nodes[i+1:])
+	} else {
+		i := g.nodePos[n.ID]
+		g.Nodes[i] = g.Nodes[last]
+		g.nodePos[g.Nodes[i].ID] = i
+		delete(g.nodePos, n.ID)
+	}
+	g.Nodes[last] = nil
+	g.Nodes = g.Nodes[:last]
+
+	delete(g.nodeIndex, n.ID)
+	g.NumNodes--
+}
+
 // RemoveNode removes a node from a graph and deletes the metadata from that node.
 // It is guaranteed to complete even in the event of errors
 func (g *Graph) RemoveNode(n1 *Node) {
 	g.Lock.Lock()
 	defer g.Lock.Unlock()
 
-	// Delete all edges to and from this node
-	for _, n2 := range g.Nodes {
-		g.removeEdgeHelper(n2, n1, true)
+	// Delete all edges to and from this node. Edge lists are copied because
+	// they shrink as edges are removed
+	outEdges := append([]*Edge(nil), n1.Edges...)
+	for _, e := range outEdges {
+		g.removeEdgeHelper2(n1, e.Nodes[1].Node)
 	}
-	for i, n := range g.Nodes {
-		if n.ID == n1.ID {
-			if i == len(g.Nodes)-1 {
-				g.Nodes = g.Nodes[:i]
-			} else {
-				g.Nodes = append(g.Nodes[:i], g.Nodes[i+1:]...)
-			}
-			g.NumNodes--
-		}
+	inEdges := append([]*Edge(nil), n1.inEdges...)
+	for _, e := range inEdges {
+		g.removeEdgeHelper2(e.Nodes[0].Node, n1)
 	}
+	g.removeNodeHelper(n1)
 
 	// Delete node data
 	n1.Extra.DeleteData()
@@ -398,9 +509,16 @@ func (g *Graph) GetRelativeByID(n1 int, tag string) (*Node, error) {
 
 // GetEdge returns the edge from n1 to n2
 func (g *Graph) GetEdge(n1 *Node, n2 int) (*Edge, error) {
-	for _, e := range n1.Edges {
-		if e.Nodes[1].ID == n2 {
-			return e, nil
+	if g.order == SortedOrder {
+		i := searchEdges(n1.Edges, n2)
+		if i < len(n1.Edges) && n1.Edges[i].GetValue() == n2 {
+			return n1.Edges[i], nil
+		}
+	} else {
+		for _, e := range n1.Edges {
+			if e.Nodes[1].ID == n2 {
+				return e, nil
+			}
 		}
 	}
 
@@ -458,7 +576,15 @@ func (g *Graph) setEdgeHelper2(n1, n2 *Node, w float64, t1, t2 string) error {
 	e.Weight = w
 
 	if newEdge {
-		n1.Edges = append(n1.Edges, e)
+		if g.order == SortedOrder {
+			i := searchEdges(n1.Edges, n2.ID)
+			n1.Edges = append(n1.Edges, nil)
+			copy(n1.Edges[i+1:], n1.Edges[i:])
+			n1.Edges[i] = e
+		} else {
+			n1.Edges = append(n1.Edges, e)
+		}
+		n2.inEdges = append(n2.inEdges, e)
 		g.NumEdges++
 	}
 
@@ -509,9 +635,52 @@ func (g *Graph) SetEdgeByNodeID(n1, n2 int, w float64, t1, t2 string, bidirectio
 	return g.SetEdge(node1, node2, w, t1, t2, bidirectional)
 }
 
+// setEdgeCostHelper is a non-locking version of SetEdgeCost
+func (g *Graph) setEdgeCostHelper(n1, n2 *Node, c float64, bidirectional bool) error {
+	e, err := g.GetEdge(n1, n2.ID)
+	if err != nil {
+		return err
+	}
+	e.Cost = c
+
+	if bidirectional {
+		e, err = g.GetEdge(n2, n1.ID)
+		if err != nil {
+			return err
+		}
+		e.Cost = c
+	}
+
+	return nil
+}
+
+// SetEdgeCost sets the cost of the existing edge from n1 to n2 to c. If
+// bidirectional is true, then the cost of the reverse edge will also be set
+func (g *Graph) SetEdgeCost(n1, n2 *Node, c float64, bidirectional bool) error {
+	g.Lock.Lock()
+	defer g.Lock.Unlock()
+
+	return g.setEdgeCostHelper(n1, n2, c, bidirectional)
+}
+
+// SetEdgeCostByNodeID sets the cost of the existing edge from n1 to n2 to c. If
+// bidirectional is true, then the cost of the reverse edge will also be set
+func (g *Graph) SetEdgeCostByNodeID(n1, n2 int, c float64, bidirectional bool) error {
+	node1, err := g.GetNodeByID(n1)
+	if err != nil {
+		return err
+	}
+	node2, err := g.GetNodeByID(n2)
+	if err != nil {
+		return err
+	}
+
+	return g.SetEdgeCost(node1, node2, c, bidirectional)
+}
+
 // removeEdgeHelper2 is a non-locking, unidirectional version of remove edge
 func (g *Graph) removeEdgeHelper2(n1, n2 *Node) error {
-	_, err := g.GetEdge(n1, n2.ID)
+	e, err := g.GetEdge(n1, n2.ID)
 	var errCheck *NoEdgeError
 	errCheckOk := errors.As(err, &errCheck)
 	if err != nil && !errCheckOk {
@@ -520,20 +689,50 @@ func (g *Graph) removeEdgeHelper2(n1, n2 *Node) error {
 		return nil
 	}
 
-	for i, e := range n1.Edges {
-		if e.Nodes[1].ID == n2.ID {
-			if i == len(n1.Edges)-1 {
-				n1.Edges = n1.Edges[:i]
-			} else {
-				n1.Edges = append(n1.Edges[:i], n1.Edges[i+1:]...)
-			}
-			break
-		}
+	if g.order == SortedOrder {
+		i := searchEdges(n1.Edges, n2.ID)
+		n1.Edges = append(n1.Edges[:i], n1.Edges[i+1:]...)
+	} else {
+		n1.Edges = removeEdgeFromList(n1.Edges, e)
 	}
+	n2.inEdges = removeEdgeFromList(n2.inEdges, e)
+	g.NumEdges--
 
 	return nil
 }
 
+// searchNodes returns the position of the node with the specified ID in a list
+// of nodes sorted by Comparable value, or the position at which it would be
+// inserted
+func searchNodes(nodes []*Node, id int) int {
+	return sort.Search(len(nodes), func(i int) bool {
+		return nodes[i].GetValue() >= id
+	})
+}
+
+// searchEdges returns the position of the edge to the far node with the
+// specified ID in a list of edges sorted by Comparable value, or the position
+// at which it would be inserted
+func searchEdges(edges []*Edge, id int) int {
+	return sort.Search(len(edges), func(i int) bool {
+		return edges[i].GetValue() >= id
+	})
+}
+
+// removeEdgeFromList removes edge e from a list of edges, preserving the order
+// of the remaining edges
+func removeEdgeFromList(edges []*Edge, e *Edge) []*Edge {
+	for i, e2 := range edges {
+		if e2 == e {
+			if i == len(edges)-1 {
+				return edges[:i]
+			}
+			return append(edges[:i], edges[i+1:]...)
+		}
+	}
+	return edges
+}
+
 // removeEdgeHelper is a non-locking version of remove edge, so that it can be
 // called internally without blocking issues
 // If bidirectional and both nodes exist, must process both cases even on error of first case
@@ -557,6 +756,36 @@ func (g *Graph) RemoveEdge(n1, n2 *Node, bidirectional bool) error {
 	return g.removeEdgeHelper(n1, n2, bidirectional)
 }
 
+// InEdges returns the edges whose far node is n. The returned list is owned by
+// the graph and must not be modified
+func (g *Graph) InEdges(n *Node) []*Edge {
+	return n.inEdges
+}
+
+// OutEdges returns the edges whose near node is n. The returned list is owned
+// by the graph and must not be modified
+func (g *Graph) OutEdges(n *Node) []*Edge {
+	return n.Edges
+}
+
+// Predecessors returns the nodes with an edge to n
+func (g *Graph) Predecessors(n *Node) []*Node {
+	nodes := make([]*Node, len(n.inEdges))
+	for i, e := range n.inEdges {
+		nodes[i] = e.Nodes[0].Node
+	}
+	return nodes
+}
+
+// Successors returns the nodes with an edge from n
+func (g *Graph) Successors(n *Node) []*Node {
+	nodes := make([]*Node, len(n.Edges))
+	for i, e := range n.Edges {
+		nodes[i] = e.Nodes[1].Node
+	}
+	return nodes
+}
+
 // RemoveEdgeByNodeID removes the edge between n1 and n2
 func (g *Graph) RemoveEdgeByNodeID(n1, n2 int, bidirectional bool) error {
 	node1, err := g.GetNodeByID(n1)
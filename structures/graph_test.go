@@ -1,10 +1,13 @@
 package structures
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -275,6 +278,195 @@ func TestGraph(t *testing.T) {
 		}
 	})
 
+	t.Run("Graph incoming edges", func(t *testing.T) {
+		g := NewGraph(100)
+		data := mockData{42}
+		for i := 0; i < 10; i++ {
+			g.SetNodeByID(i, float64(i), float64(i), 0.0, data)
+		}
+
+		// Node 0 points at nodes 1 through 4, and nodes 5 through 8 point at node 0
+		for i := 1; i < 5; i++ {
+			g.SetEdgeByNodeID(0, i, float64(i), "p", "c", false)
+		}
+		for i := 5; i < 9; i++ {
+			g.SetEdgeByNodeID(i, 0, float64(i), "c", "p", false)
+		}
+		g.SetEdgeByNodeID(1, 2, 1, "s", "s", true)
+
+		n0, _ := g.GetNodeByID(0)
+		n2, _ := g.GetNodeByID(2)
+		if len(g.OutEdges(n0)) != 4 || len(g.InEdges(n0)) != 4 {
+			t.Fatalf(fmt.Sprintf("Node %d should have 4 outgoing and 4 incoming edges", n0.ID))
+		}
+		checkNodeIDs(t, g.Successors(n0), []int{1, 2, 3, 4})
+		checkNodeIDs(t, g.Predecessors(n0), []int{5, 6, 7, 8})
+		checkNodeIDs(t, g.Predecessors(n2), []int{0, 1})
+		checkNodeIDs(t, g.Successors(n2), []int{1})
+
+		// Removing an edge must remove it from both adjacency lists
+		g.RemoveEdgeByNodeID(0, 2, false)
+		checkNodeIDs(t, g.Predecessors(n2), []int{1})
+		checkNodeIDs(t, g.Successors(n0), []int{1, 3, 4})
+		if g.NumEdges != 9 {
+			t.Fatalf(fmt.Sprintf("Graph should have %d edges", 9))
+		}
+
+		// Removing a node must remove every edge to and from it
+		g.RemoveNode(n0)
+		for _, n := range g.Nodes {
+			for _, e := range g.OutEdges(n) {
+				if e.Nodes[1].ID == n0.ID {
+					t.Fatalf(fmt.Sprintf("Edge from %d to removed node %d remains", n.ID, n0.ID))
+				}
+			}
+			for _, e := range g.InEdges(n) {
+				if e.Nodes[0].ID == n0.ID {
+					t.Fatalf(fmt.Sprintf("Edge from removed node %d to %d remains", n0.ID, n.ID))
+				}
+			}
+		}
+		if g.NumEdges != 2 {
+			t.Fatalf(fmt.Sprintf("Graph should have %d edges", 2))
+		}
+	})
+
+	t.Run("Graph edge costs", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// The second edge has no cost column
+		csvText := "3,3,10\n0,0,0,0\n1,1,0,0\n2,2,0,0\n0,1,5,2.5\n1,2,3\n2,0,4,-1\n"
+		mgr, err := LoadCSV(ctx, cancel, csvText)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not load CSV: %v", err))
+		}
+		g := mgr.Graph
+		for _, want := range []struct {
+			n1, n2 int
+			w, c   float64
+		}{{0, 1, 5, 2.5}, {1, 2, 3, 0}, {2, 0, 4, -1}} {
+			e, err := g.GetEdgeByNodeID(want.n1, want.n2)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not find edge from %d to %d", want.n1, want.n2))
+			}
+			if e.Weight != want.w || e.Cost != want.c {
+				t.Fatalf(
+					fmt.Sprintf(
+						"Edge from %d to %d should have weight %f and cost %f but has %f and %f",
+						want.n1, want.n2, want.w, want.c, e.Weight, e.Cost,
+					),
+				)
+			}
+		}
+
+		g.SetEdgeByNodeID(1, 0, 1, "n", "n", false)
+		if err = g.SetEdgeCostByNodeID(0, 1, 7, true); err != nil {
+			t.Fatalf(fmt.Sprintf("Could not set edge cost: %v", err))
+		}
+		e, _ := g.GetEdgeByNodeID(1, 0)
+		if e.Cost != 7 {
+			t.Fatalf(fmt.Sprintf("Reverse edge should have cost %f but has %f", 7.0, e.Cost))
+		}
+
+		var errCheck *NoEdgeError
+		if err = g.SetEdgeCostByNodeID(0, 2, 1, false); !errors.As(err, &errCheck) {
+			t.Fatalf("Setting the cost of a missing edge should fail with NoEdgeError")
+		}
+
+		// A cost on an edge to a missing node fails the load
+		var noNode *NoNodeError
+		csvText = "2,1,10\n0,0,0,0\n1,1,0,0\n0,5,1,2\n"
+		if _, err = LoadCSV(ctx, cancel, csvText); !errors.As(err, &noNode) {
+			t.Fatalf(fmt.Sprintf("Loading a cost for an edge to a missing node should fail with NoNodeError but got %v", err))
+		}
+	})
+
+	t.Run("Graph node index", func(t *testing.T) {
+		g := NewGraph(100)
+		data := mockData{42}
+		for i := 0; i < 100; i++ {
+			g.SetNodeByID(i, float64(i), float64(i), 0.0, data)
+		}
+
+		// Remove nodes from the front, middle and back of the node list
+		for _, id := range []int{0, 50, 99, 1, 98} {
+			g.RemoveNodeByID(id)
+			if g.HasNodeWithID(id) {
+				t.Fatalf(fmt.Sprintf("Node %d should not be indexed after removal", id))
+			}
+		}
+		if g.NumNodes != 95 || len(g.Nodes) != 95 {
+			t.Fatalf(fmt.Sprintf("Graph should have %d nodes", 95))
+		}
+		checkNodeIndex(t, g)
+
+		// Re-adding a removed node must index it again
+		g.SetNodeByID(50, 50, 50, 0, data)
+		checkNodeIndex(t, g)
+		if !g.HasNodeWithID(50) {
+			t.Fatalf(fmt.Sprintf("Node %d should be indexed after re-adding", 50))
+		}
+	})
+
+	t.Run("Sorted graph storage", func(t *testing.T) {
+		g := NewGraphWithOrder(100, SortedOrder)
+		data := mockData{42}
+		ids := []int{7, 3, 9, 0, 5, 1, 8, 2, 6, 4}
+		for _, id := range ids {
+			g.SetNodeByID(id, float64(id), float64(id), 0.0, data)
+		}
+		checkNodesSorted(t, g)
+
+		for _, id := range ids[1:] {
+			err := g.SetEdgeByNodeID(ids[0], id, float64(id), "p", "c", false)
+			if err != nil {
+				t.Fatalf(fmt.Sprintf("Could not add edge from %d to %d", ids[0], id))
+			}
+		}
+		n, _ := g.GetNodeByID(ids[0])
+		checkEdgesSorted(t, n)
+		for _, id := range ids[1:] {
+			e, err := g.GetEdge(n, id)
+			if err != nil || e.Nodes[1].ID != id {
+				t.Fatalf(fmt.Sprintf("Could not retrieve edge from %d to %d", n.ID, id))
+			}
+		}
+		checkRemoveEdge(t, g, n, n)
+
+		g.RemoveEdgeByNodeID(ids[0], 5, false)
+		g.RemoveEdgeByNodeID(ids[0], 0, false)
+		checkEdgesSorted(t, n)
+		if _, err := g.GetEdgeByNodeID(ids[0], 5); err == nil {
+			t.Fatalf(fmt.Sprintf("Edge from %d to %d not removed from graph properly", ids[0], 5))
+		}
+
+		g.RemoveNodeByID(3)
+		g.RemoveNodeByID(0)
+		g.RemoveNodeByID(9)
+		checkNodesSorted(t, g)
+		checkNodeIndex(t, g)
+		if g.NumNodes != 7 {
+			t.Fatalf(fmt.Sprintf("Graph should have %d nodes", 7))
+		}
+
+		// Switching storage order must sort nodes and edges added out of order
+		g = NewGraph(100)
+		for _, id := range ids {
+			g.SetNodeByID(id, float64(id), float64(id), 0.0, data)
+		}
+		for _, id := range ids[1:] {
+			g.SetEdgeByNodeID(ids[0], id, float64(id), "p", "c", false)
+		}
+		g.SetStorageOrder(SortedOrder)
+		checkNodesSorted(t, g)
+		n, _ = g.GetNodeByID(ids[0])
+		checkEdgesSorted(t, n)
+		g.SetStorageOrder(InsertionOrder)
+		g.RemoveNodeByID(5)
+		checkNodeIndex(t, g)
+	})
+
 	t.Run("Random unidirectional graph", func(t *testing.T) {
 		//TODO after RandomUnidirectionalGraph() is rewritten
 	})
@@ -289,6 +481,88 @@ func TestGraph(t *testing.T) {
 	fmt.Println()
 }
 
+func BenchmarkGetNodeByID(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		g := NewGraph(100)
+		for i := 0; i < size; i++ {
+			g.SetNodeByID(i, float64(i), float64(i), 0.0, mockData{i})
+		}
+		// Stride through IDs so that lookups are spread over the node list
+		b.Run(fmt.Sprintf("Indexed/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				g.GetNodeByID((i * 7919) % size)
+			}
+		})
+		b.Run(fmt.Sprintf("LinearScan/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				linearNodeScan(g, (i*7919)%size)
+			}
+		})
+	}
+}
+
+func BenchmarkGetEdge(b *testing.B) {
+	degree := 10000
+	for _, order := range []StorageOrder{InsertionOrder, SortedOrder} {
+		g := NewGraphWithOrder(100, order)
+		hub, _ := g.SetNodeByID(-1, 0, 0, 0, mockData{0})
+		for i := 0; i < degree; i++ {
+			n, _ := g.SetNodeByID(i, float64(i), float64(i), 0.0, mockData{i})
+			g.SetEdge(hub, n, 1, "p", "c", false)
+		}
+		name := "InsertionOrder"
+		if order == SortedOrder {
+			name = "SortedOrder"
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				g.GetEdge(hub, (i*7919)%degree)
+			}
+		})
+	}
+}
+
+func BenchmarkLoadCSV(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, size := range []int{1000, 10000, 100000} {
+		csvText := mockCSV(size, size)
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := LoadCSV(ctx, cancel, csvText)
+				if err != nil {
+					b.Fatalf(fmt.Sprintf("Could not load CSV: %v", err))
+				}
+			}
+		})
+	}
+}
+
+// linearNodeScan is the reference lookup used to compare against the node
+// index
+func linearNodeScan(g *Graph, id int) *Node {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// mockCSV builds CSV text in the LoadCSV format with n nodes along a line and
+// e edges between consecutive nodes
+func mockCSV(n, e int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d,%d,%d\n", n, e, 100)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "%d,%d,%d,%d\n", i, i, i, 0)
+	}
+	for i := 0; i < e; i++ {
+		fmt.Fprintf(&b, "%d,%d,%d\n", i%n, (i+1)%n, i%100)
+	}
+	return b.String()
+}
+
 type mockData struct {
 	D int
 }
@@ -333,6 +607,66 @@ func checkEdge(t *testing.T, g *Graph, n1, n2 *Node, w float64, t1, t2 string) {
 	}
 }
 
+func checkNodeIndex(t *testing.T, g *Graph) {
+	t.Helper()
+	if len(g.nodeIndex) != len(g.Nodes) {
+		t.Fatalf(
+			fmt.Sprintf("Node index size %d should match node count %d",
+				len(g.nodeIndex), len(g.Nodes)),
+		)
+	}
+	if g.order == InsertionOrder && len(g.nodePos) != len(g.Nodes) {
+		t.Fatalf(
+			fmt.Sprintf("Node position index size %d should match node count %d",
+				len(g.nodePos), len(g.Nodes)),
+		)
+	}
+	for i, n := range g.Nodes {
+		if g.nodeIndex[n.ID] != n {
+			t.Fatalf(fmt.Sprintf("Node %d is indexed incorrectly", n.ID))
+		}
+		if g.order == InsertionOrder && g.nodePos[n.ID] != i {
+			t.Fatalf(fmt.Sprintf("Node %d position is indexed incorrectly", n.ID))
+		}
+	}
+}
+
+func checkNodesSorted(t *testing.T, g *Graph) {
+	t.Helper()
+	for i := 1; i < len(g.Nodes); i++ {
+		if g.Nodes[i-1].Compare(g.Nodes[i]) >= 0 {
+			t.Fatalf(
+				fmt.Sprintf("Node %d should be ordered before node %d",
+					g.Nodes[i-1].ID, g.Nodes[i].ID),
+			)
+		}
+	}
+}
+
+func checkEdgesSorted(t *testing.T, n *Node) {
+	t.Helper()
+	for i := 1; i < len(n.Edges); i++ {
+		if n.Edges[i-1].Compare(n.Edges[i]) >= 0 {
+			t.Fatalf(
+				fmt.Sprintf("Edge to %d should be ordered before edge to %d",
+					n.Edges[i-1].Nodes[1].ID, n.Edges[i].Nodes[1].ID),
+			)
+		}
+	}
+}
+
+func checkNodeIDs(t *testing.T, nodes []*Node, ids []int) {
+	t.Helper()
+	if len(nodes) != len(ids) {
+		t.Fatalf(fmt.Sprintf("Expected %d nodes but got %d", len(ids), len(nodes)))
+	}
+	for i, n := range nodes {
+		if n.ID != ids[i] {
+			t.Fatalf(fmt.Sprintf("Expected node %d at position %d but got %d", ids[i], i, n.ID))
+		}
+	}
+}
+
 func checkRemoveEdge(t *testing.T, g *Graph, n1, n2 *Node) {
 	t.Helper()
 	_, err := g.GetEdge(n1, n2.ID)
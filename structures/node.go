@@ -44,6 +44,10 @@ type Node struct {
 	Extra  Data    `json:"extra"`
 	Coords Point   `json:"coords"`
 	Edges  []*Edge `json:"edges"`
+
+	// inEdges holds the edges whose far node is this node. It is maintained by
+	// the graph that owns the node
+	inEdges []*Edge
 }
 
 func (n *Node) String() string {
@@ -49,6 +49,8 @@ type ColorData struct {
 	Color  string `json:"color"`
 	Type   string `json:"type"`
 	Height int    `json:"height"`
+	// Size scales the displayed node, where 0 means the default size
+	Size float64 `json:"size,omitempty"`
 }
 
 func (c ColorData) GetData() interface{} {
@@ -207,13 +209,18 @@ func (t *RBTree) String() string {
 }
 
 func NewRBTree(ctx context.Context, cancel context.CancelFunc) *RBTree {
+	return newRBTree(ctx, cancel, NewRBIDDistributor())
+}
+
+// newRBTree creates a red-black tree whose node IDs come from idDistributor
+func newRBTree(ctx context.Context, cancel context.CancelFunc, idDistributor IDDistributor) *RBTree {
 	t := new(RBTree)
 	t.lock = &sync.Mutex{}
 	t.updated = make(chan struct{})
 	t.cancel = cancel
 	t.ctx = ctx
 
-	t.idDistributor = NewRBIDDistributor()
+	t.idDistributor = idDistributor
 
 	t.Graph = NewGraph(1.0)
 	t.Type = RBTreeType
@@ -237,8 +244,16 @@ func (t *RBTree) Updated() <-chan struct{} {
 // It is the prerogative of graph owners (i.e. end-users, accompanying
 // structures, or algorithms) to call OnUpdate()
 func (t *RBTree) OnUpdate() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// The lock is held while sending so that Done cannot close updated
+	// underneath the send
 	if !t.isDone {
-		t.updated <- struct{}{}
+		select {
+		case t.updated <- struct{}{}:
+		case <-t.ctx.Done():
+		}
 	}
 }
 
@@ -246,9 +261,14 @@ func (t *RBTree) OnUpdate() {
 // It is the prerogative of graph owners (i.e. end-users, accompanying
 // structures, or algorithms) to call Done()
 func (t *RBTree) Done() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
 	//t.cancel()
-	close(t.updated)
-	t.isDone = true
+	if !t.isDone {
+		close(t.updated)
+		t.isDone = true
+	}
 }
 
 // Lock is useful to be called when the graph needs to be accessed as an atomic
@@ -315,7 +335,6 @@ func (t *RBTree) putNode(parent *Node, tag, nodeTypeTag, color string) error {
 
 		t.Root = n
 		t.Height = 0
-		t.nodeHeights[0] = 1
 
 		// Set nil node as parent of root
 		id = t.idDistributor.GetID(NilNodeTag, t.Graph.HasNodeWithID)
@@ -575,7 +594,10 @@ func (t *RBTree) setColor(n *Node, color string) error {
 }
 
 func (t *RBTree) setHeightRecurse(n *Node, x, y, z float64, data ColorData, prevHeight int, fromPriorNode bool) error {
-	if fromPriorNode {
+	// Nil leaves are display placeholders, so only data nodes count towards
+	// the tree height
+	isData := data.Type == DataNodeTag
+	if fromPriorNode && isData {
 		t.nodeHeights[prevHeight]--
 		for t.nodeHeights[t.Height] == 0 {
 			if t.Height == 0 {
@@ -585,14 +607,16 @@ func (t *RBTree) setHeightRecurse(n *Node, x, y, z float64, data ColorData, prev
 		}
 	}
 	t.Graph.SetNode(n, n.ID, x, y, z, data)
-	_, ok := t.nodeHeights[data.Height]
-	if !ok {
-		t.nodeHeights[data.Height] = 1
-	} else {
-		t.nodeHeights[data.Height]++
-	}
-	if data.Height > t.Height {
-		t.Height = data.Height
+	if isData {
+		_, ok := t.nodeHeights[data.Height]
+		if !ok {
+			t.nodeHeights[data.Height] = 1
+		} else {
+			t.nodeHeights[data.Height]++
+		}
+		if data.Height > t.Height {
+			t.Height = data.Height
+		}
 	}
 
 	var errCheck *NoEdgeError
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -25,7 +26,7 @@ func TestRBTree(t *testing.T) {
 		if tree.Height != 0 {
 			t.Fatalf("Tree must begin with height 0")
 		}
-		if tree.numMaxHeightNodes != 1 {
+		if tree.nodeHeights[0] != 1 {
 			t.Fatalf("Tree must begin with root node at height 0")
 		}
 
@@ -115,18 +116,24 @@ func TestRBTree(t *testing.T) {
 			t.Fatalf("Could not get root parent as grandparent of root rchild")
 		}
 
-		mockNode := NewNode()
-		err = tree.setLChild(n3, mockNode, true, true)
+		mockNode, err := tree.NewNode(DataNodeTag)
+		if err != nil {
+			t.Fatalf("Could not create new data node")
+		}
+		err = tree.setLChild(n3, mockNode, true, true, false)
+		if err != nil {
+			t.Fatalf(fmt.Sprintf("Could not set %d as lchild of %d", mockNode.ID, n3.ID))
+		}
 
 		n4, err := tree.GetUncle(mockNode)
 		if err != nil || !reflect.DeepEqual(n2, n4) {
-			t.Fatalf(fmt.Sprintf("Could not get %d as uncle of %d", n2.ID, n4.ID))
+			t.Fatalf(fmt.Sprintf("Could not get %d as uncle of %d", n2.ID, mockNode.ID))
 		}
 
 		err = tree.setColor(n3, Colors["black"])
 		n3ColorData, ok := ColorDataFromData(n3.Extra)
 		if !ok || n3ColorData.Color != Colors["black"] {
-			t.Fatalf(fmt.Sprintf("Color of %d should be %d", n3.ID, Colors["black"]))
+			t.Fatalf(fmt.Sprintf("Color of %d should be %s", n3.ID, Colors["black"]))
 		}
 	})
 
@@ -328,11 +335,11 @@ func TestRBTree(t *testing.T) {
 		n, _ = tree.GetLChild(n)
 		n1, _ = treeCopy.GetLChild(n)
 		s1, _ := treeCopy.GetSibling(n1)
-		s11, _ := treeCopy.GetLChild(s1)
+		s12, _ := treeCopy.GetRChild(s1)
 		nData, _ := ColorDataFromData(n.Extra)
 		treeCopy.setColor(s1, nData.Color)
 		treeCopy.setColor(n, Colors["black"])
-		treeCopy.setColor(s11, Colors["black"])
+		treeCopy.setColor(s12, Colors["black"])
 		treeCopy.rotateLeft(n)
 		if !reflect.DeepEqual(tree.Graph.String(), treeCopy.Graph.String()) {
 			t.Fatalf("tree and treeCopy must be equal after deleteCase6")
@@ -381,7 +388,10 @@ func TestRBTree(t *testing.T) {
 func newMockRBTree(ctx context.Context, cancel context.CancelFunc, t *testing.T) *RBTree {
 	t.Helper()
 
-	tree := NewRBTree(ctx, cancel)
+	// Seed the IDs so that mock trees built alike are equal
+	distributor := NewRBIDDistributor()
+	distributor.randNumGen = rand.New(rand.NewSource(1))
+	tree := newRBTree(ctx, cancel, distributor)
 	tree.GetParent(tree.Root)
 
 	// Set up level 1